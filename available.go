@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// Available represents a function to be used as Command.Available
+// values. It returns nil when the Command can be used and otherwise an
+// error explaining why not (which completes the sentence "not available
+// because ..."). Since it is called during completion (for only the
+// subcommands being completed) and help, it should be quick. See
+// NeedsExec, NeedsFile, NeedsUser, NeedsEnv, and NeedsAll.
+//
+type Available func() error
+
+// Unavailable returns the NotAvailable error for x if its Available
+// function reports a reason or nil if it is available (or has no
+// Available function). Available is called every time so that the
+// answer is always current (within a Shell, for example).
+//
+func (x *Command) Unavailable() error {
+	if x == nil || x.Available == nil {
+		return nil
+	}
+	if err := x.Available(); err != nil {
+		return NotAvailable(x.Name, err)
+	}
+	return nil
+}
+
+// NeedsExec returns an Available function requiring that each of the
+// executables be found in the PATH (see exec.LookPath).
+func NeedsExec(names ...string) Available {
+	return func() error {
+		for _, name := range names {
+			if _, err := exec.LookPath(name); err != nil {
+				return fmt.Errorf("%v is not in PATH", name)
+			}
+		}
+		return nil
+	}
+}
+
+// NeedsFile returns an Available function requiring that each of the
+// files (or directories) exist (see util.Found).
+func NeedsFile(paths ...string) Available {
+	return func() error {
+		for _, path := range paths {
+			if !util.Found(path) {
+				return fmt.Errorf("%v does not exist", path)
+			}
+		}
+		return nil
+	}
+}
+
+// NeedsUser returns an Available function requiring that the current
+// user (see util.User) has one of the usernames.
+func NeedsUser(names ...string) Available {
+	return func() error {
+		if util.InSlice(util.User.Username, names) {
+			return nil
+		}
+		return fmt.Errorf("%v is not %v", util.User.Username,
+			strings.Join(names, " or "))
+	}
+}
+
+// NeedsEnv returns an Available function requiring that each of the
+// environment variables be set to something other than an empty string.
+func NeedsEnv(names ...string) Available {
+	return func() error {
+		for _, name := range names {
+			if os.Getenv(name) == "" {
+				return fmt.Errorf("%v is not set", name)
+			}
+		}
+		return nil
+	}
+}
+
+// NeedsAll returns an Available function requiring all of the others
+// (returning the reason from the first that is not).
+func NeedsAll(checks ...Available) Available {
+	return func() error {
+		for _, check := range checks {
+			if err := check(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
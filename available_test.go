@@ -0,0 +1,80 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/comp"
+)
+
+func ExampleCommand_Available() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "deploy", "status")
+	deploy := cmdbox.Add("foo deploy")
+	deploy.Summary = "deploy it"
+	deploy.Available = cmdbox.NeedsEnv("CMDBOX_TEST_TOKEN")
+	deploy.Method = func(args ...string) error {
+		fmt.Println("deploying")
+		return nil
+	}
+	status := cmdbox.Add("foo status")
+	status.Summary = "show status"
+
+	os.Unsetenv("CMDBOX_TEST_TOKEN")
+	fmt.Println(cmdbox.Call(nil, "foo", "deploy"))
+	fmt.Println(x.Titles(0, 0))
+	comp.This = "foo "
+	x.Complete()
+
+	os.Setenv("CMDBOX_TEST_TOKEN", "secret")
+	defer os.Unsetenv("CMDBOX_TEST_TOKEN")
+	cmdbox.Call(nil, "foo", "deploy")
+	fmt.Println(x.Titles(0, 0))
+	x.Complete()
+	comp.This = ""
+
+	// Output:
+	// foo deploy is not available because CMDBOX_TEST_TOKEN is not set
+	// status - show status
+	// status
+	// deploying
+	// deploy - deploy it
+	// status - show status
+	// deploy
+	// status
+}
+
+func ExampleNeedsAll() {
+	check := cmdbox.NeedsAll(
+		cmdbox.NeedsFile(os.TempDir()),
+		cmdbox.NeedsExec("cmdbox-no-such-executable"),
+	)
+	fmt.Println(check())
+	fmt.Println(cmdbox.NeedsFile("/no/such/file")())
+	fmt.Println(cmdbox.NeedsUser("nobody-at-all")() != nil)
+
+	// Output:
+	// cmdbox-no-such-executable is not in PATH
+	// /no/such/file does not exist
+	// true
+}
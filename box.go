@@ -0,0 +1,403 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/rwxrob/cmdbox/term"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// Box is an independent command register with its own main Command,
+// settings, error constructors, output streams, and middleware so that
+// a program can host more than one set of Commands and tests can run
+// in parallel. The package functions (Add, Get, Call, Execute, and so
+// on) all use the Default Box. Commands added to a Box (see Box.Add)
+// keep a reference to it (see Command.Box) so that their own methods
+// (Call, Resolve, AddHelp, and such) stay within it.
+//
+// The settings have the same meaning as the package variables of the
+// same names (a zero MaxDepth means the package MaxDepth, and plugins
+// are off if either PluginsOff is set). The Theme (see HelpTheme),
+// error constructors, and output streams default to the package ones
+// when nil. Whether output is to a terminal (for color and the default
+// output format) is only checked when Stdout is os.Stdout.
+//
+// The Default Box uses the package variables (Reg, Main, DEBUG,
+// DoNotExit, Color, ForceColor, Abbrev, Prompt, Format, MaxDepth,
+// NamespaceDups, PluginsOff, HelpTheme) instead of its own fields so
+// that existing code that assigns them keeps working.
+//
+// Everything else remains shared by every Box: the error constructors
+// other than UsageError, Unimplemented, and Unresolvable (Ambiguous,
+// MissingArg, NotAvailable, and such), the plugin search path and
+// cache (see Plugin), the Shell functions and line editor (see
+// ShellReadLine) since there is only one standard input, and the
+// completion context (see comp.This) since there is only one
+// completion per process.
+//
+type Box struct {
+	Reg  *CommandMap
+	Main *Command
+
+	DEBUG      bool
+	DoNotExit  bool
+	Color      bool
+	ForceColor bool
+	Abbrev     bool
+	Prompt     bool
+	Format     string
+	MaxDepth   int
+	PluginsOff bool
+	Theme      *Theme
+
+	NamespaceDups bool
+
+	Stdout io.Writer
+	Stderr io.Writer
+
+	UsageError    func(x *Command) error
+	Unimplemented func(name string) error
+	Unresolvable  func(msg string) error
+
+	middleware []Middleware
+	nsmu       sync.Mutex
+	namespaces map[string]bool
+}
+
+// Default is the Box used by all the package functions.
+var Default = &Box{Reg: Reg, Color: true}
+
+// NewBox returns a new Box with an empty register and the same default
+// settings as the package (Color on, everything else off).
+//
+func NewBox() *Box {
+	return &Box{Reg: NewCommandMap(), Color: true}
+}
+
+// Box returns the Box the Command was added to (or Default if it was
+// never added to one).
+//
+func (x *Command) Box() *Box {
+	if x.box == nil {
+		return Default
+	}
+	return x.box
+}
+
+// ----------------------- settings (see Default) ----------------------
+
+func (b *Box) reg() *CommandMap {
+	if b == Default {
+		return Reg
+	}
+	return b.Reg
+}
+
+func (b *Box) main() *Command {
+	if b == Default {
+		return Main
+	}
+	return b.Main
+}
+
+func (b *Box) setMain(x *Command) {
+	if b == Default {
+		Main = x
+		return
+	}
+	b.Main = x
+}
+
+func (b *Box) debug() bool {
+	if b == Default {
+		return DEBUG
+	}
+	return b.DEBUG
+}
+
+func (b *Box) doNotExit() bool {
+	if b == Default {
+		return DoNotExit
+	}
+	return b.DoNotExit
+}
+
+func (b *Box) abbrev() bool {
+	if b == Default {
+		return Abbrev
+	}
+	return b.Abbrev
+}
+
+func (b *Box) namespaceDups() bool {
+	if b == Default {
+		return NamespaceDups
+	}
+	return b.NamespaceDups
+}
+
+func (b *Box) prompt() bool {
+	if b == Default {
+		return Prompt
+	}
+	return b.Prompt
+}
+
+func (b *Box) format() string {
+	if b == Default {
+		return Format
+	}
+	return b.Format
+}
+
+// colorOn is the Box equivalent of the package colorOn.
+func (b *Box) colorOn() bool {
+	if b == Default {
+		return colorOn()
+	}
+	if b.ForceColor {
+		return true
+	}
+	return b.Color && os.Getenv("NO_COLOR") == "" && b.isTerminal()
+}
+
+// isTerminal returns true if output of the Box is to an interactive
+// terminal (never when Stdout has been assigned something else).
+func (b *Box) isTerminal() bool {
+	return b.stdout() == os.Stdout && term.IsTerminal()
+}
+
+func (b *Box) pluginsOff() bool {
+	if b == Default {
+		return PluginsOff
+	}
+	return PluginsOff || b.PluginsOff
+}
+
+func (b *Box) theme() *Theme {
+	if b == Default || b.Theme == nil {
+		return HelpTheme
+	}
+	return b.Theme
+}
+
+func (b *Box) maxDepth() int {
+	if b == Default || b.MaxDepth <= 0 {
+		return MaxDepth
+	}
+	return b.MaxDepth
+}
+
+func (b *Box) stdout() io.Writer {
+	if b.Stdout == nil {
+		return os.Stdout
+	}
+	return b.Stdout
+}
+
+func (b *Box) stderr() io.Writer {
+	if b.Stderr == nil {
+		return os.Stderr
+	}
+	return b.Stderr
+}
+
+func (b *Box) usageError(x *Command) error {
+	if b.UsageError == nil {
+		return UsageError(x)
+	}
+	return b.UsageError(x)
+}
+
+func (b *Box) unimplemented(name string) error {
+	if b.Unimplemented == nil {
+		return Unimplemented(name)
+	}
+	return b.Unimplemented(name)
+}
+
+func (b *Box) unresolvable(msg string) error {
+	if b.Unresolvable == nil {
+		return Unresolvable(msg)
+	}
+	return b.Unresolvable(msg)
+}
+
+// ----------------------------- register -----------------------------
+
+// Add is the Box equivalent of the package Add function.
+func (b *Box) Add(name string, a ...string) *Command {
+	o := origin()
+	if b.namespaceDups() {
+		name = b.namespaced(name, o)
+	}
+	var x *Command
+	for {
+		x = b.reg().Get(name)
+		if x == nil {
+			break
+		}
+		name = name + "_"
+	}
+	x = newCommand(b, name, a...)
+	x.Origin = o
+	b.reg().Set(name, x)
+	return x
+}
+
+// Get is the Box equivalent of the package Get function.
+func (b *Box) Get(name string) *Command { return b.reg().Get(name) }
+
+// Set is the Box equivalent of the package Set function. The Command is
+// also associated with the Box if it was not added to another (and
+// given an Origin if it has none).
+func (b *Box) Set(name string, x *Command) {
+	if x.box == nil && b != Default {
+		x.box = b
+	}
+	if x.Origin == nil {
+		x.Origin = origin()
+	}
+	b.reg().Set(name, x)
+}
+
+// Delete is the Box equivalent of the package Delete function.
+func (b *Box) Delete(names ...string) { b.reg().Delete(names...) }
+
+// Rename is the Box equivalent of the package Rename function.
+func (b *Box) Rename(from, to string) { b.reg().Rename(from, to) }
+
+// Names is the Box equivalent of the package Names function.
+func (b *Box) Names() []string { return b.reg().Names() }
+
+// Dups is the Box equivalent of the package Dups function.
+func (b *Box) Dups() []string { return b.reg().Dups() }
+
+// Slice is the Box equivalent of the package Slice function.
+func (b *Box) Slice(names ...string) []*Command {
+	return b.reg().Slice(names...)
+}
+
+// JSON is the Box equivalent of the package JSON function.
+func (b *Box) JSON() string { return b.reg().JSON() }
+
+// Init is the Box equivalent of the package Init function.
+func (b *Box) Init() {
+	b.reg().Init()
+	b.nsmu.Lock()
+	b.namespaces = nil
+	b.nsmu.Unlock()
+	if b == Default {
+		middleware = nil
+		return
+	}
+	b.middleware = nil
+}
+
+// Use is the Box equivalent of the package Use function.
+func (b *Box) Use(m ...Middleware) {
+	if b == Default {
+		middleware = append(middleware, m...)
+		return
+	}
+	b.middleware = append(b.middleware, m...)
+}
+
+func (b *Box) uses() []Middleware {
+	if b == Default {
+		return middleware
+	}
+	return b.middleware
+}
+
+// ------------------------------- exit -------------------------------
+
+// Exit is the Box equivalent of the package Exit function.
+func (b *Box) Exit() {
+	if !b.doNotExit() {
+		os.Exit(0)
+	}
+}
+
+// ExitError prints the error to the Box Stderr (or with the log package
+// for the Default Box) and exits with a 1 return value unless DoNotExit
+// has been set.
+//
+func (b *Box) ExitError(err error) {
+	if b == Default {
+		ExitError(err)
+		return
+	}
+	b.logError(err)
+	if !b.doNotExit() {
+		os.Exit(1)
+	}
+}
+
+// logError prints the error (unless empty) to the Box Stderr (or with
+// the log package for the Default Box).
+func (b *Box) logError(err error) {
+	out := fmt.Sprintf("%v", err)
+	switch {
+	case len(out) == 0:
+	case b == Default:
+		log.Println(out)
+	default:
+		fmt.Fprintln(b.stderr(), out)
+	}
+}
+
+// trapPanic is the Box equivalent of TrapPanic (which the Default Box
+// uses instead) exiting with ExitError of the Box. It must be deferred
+// directly.
+func (b *Box) trapPanic() {
+	if r := recover(); r != nil {
+		b.ExitError(fmt.Errorf("%v", r))
+	}
+}
+
+// Page is the Box equivalent of the package Page function. Output is
+// only paged when the Box Stdout is the standard output and otherwise
+// written to it directly (without terminal escapes unless color is
+// enabled for the Box).
+//
+func (b *Box) Page(buf string) error {
+	if b == Default {
+		return Page(buf)
+	}
+	if !b.colorOn() {
+		buf = stripEsc(buf)
+	}
+	if b.stdout() == os.Stdout {
+		return util.Page(buf)
+	}
+	_, err := fmt.Fprint(b.stdout(), buf)
+	return err
+}
+
+// debugLog logs with util.Log when DEBUG is set for the Box.
+func (b *Box) debugLog(a interface{}) {
+	if b.debug() {
+		util.Log(a)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleBox() {
+	one := cmdbox.NewBox()
+	two := cmdbox.NewBox()
+
+	for _, b := range []*cmdbox.Box{one, two} {
+		b := b
+		b.Add("greet", "hi")
+		hi := b.Add("greet hi")
+		hi.Method = func(args ...string) error {
+			fmt.Println("hi from", len(b.Names()), "commands")
+			return nil
+		}
+	}
+	two.Add("extra")
+
+	one.Call(nil, "greet", "hi")
+	two.Call(nil, "greet", "hi")
+	fmt.Println(one.Names(), two.Names())
+
+	// commands stay in their own box
+	fmt.Println(one.Get("greet").Box() == one)
+	fmt.Println(cmdbox.Get("greet") == nil)
+
+	// Output:
+	// hi from 2 commands
+	// hi from 3 commands
+	// [greet greet hi] [extra greet greet hi]
+	// true
+	// true
+}
+
+func ExampleBox_Execute() {
+	b := cmdbox.NewBox()
+	b.DoNotExit = true
+	var stderr, stdout bytes.Buffer
+	b.Stderr = &stderr
+	b.Stdout = &stdout
+
+	x := b.Add("tool")
+	x.Result = func(args ...string) (interface{}, error) {
+		return []string{"one", "two"}, nil
+	}
+	b.Format = "tsv"
+	b.Execute("tool")
+	b.Execute("nope")
+
+	fmt.Print(stdout.String())
+	fmt.Print(stderr.String())
+
+	// Output:
+	// one
+	// two
+	// unimplemented: nope
+}
+
+func TestBox_independent(t *testing.T) {
+	for _, name := range []string{"one", "two"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			b := cmdbox.NewBox()
+			b.DoNotExit = true
+			b.Stdout, b.Stderr = &stdout, &stderr
+
+			x := b.Add(name, "boom")
+			x.Summary = "the " + name + " command"
+			x.AddHelp()
+			boom := b.Add(name + " boom")
+			boom.Method = func(args ...string) error {
+				panic("boom in " + name)
+			}
+			b.Add("Not Valid")
+
+			b.Call(nil, name, "help")
+			b.Call(nil, name, "boom")
+			b.Execute(name)
+
+			out, errs := stdout.String(), stderr.String()
+			if !strings.Contains(out, "the "+name+" command") {
+				t.Errorf("help not in Stdout: %q", out)
+			}
+			if strings.Count(out, "NAME") != 2 {
+				t.Errorf("help not printed twice: %q", out)
+			}
+			if !strings.Contains(errs, "boom in "+name) {
+				t.Errorf("panic not in Stderr: %q", errs)
+			}
+			if !strings.Contains(errs, "Not Valid") {
+				t.Errorf("syntax error not in Stderr: %q", errs)
+			}
+		})
+	}
+}
+
+func ExampleBox_Theme() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	var stdout bytes.Buffer
+	b := cmdbox.NewBox()
+	b.Stdout = &stdout
+	b.ForceColor = true
+	b.Theme = cmdbox.DefaultTheme()
+	b.Theme.Header = "\x1b[7m"
+
+	x := b.Add("tool")
+	x.Result = func(args ...string) (interface{}, error) {
+		return "done", nil
+	}
+	b.Call(nil, "tool") // Stdout is not a terminal, so json
+
+	fmt.Print(stdout.String())
+	fmt.Println(strings.Contains(x.Help(), "\x1b[7mNAME"))
+	fmt.Println(strings.Contains(cmdbox.Add("tool").Help(), "\x1b[7m"))
+
+	// Output:
+	// "done"
+	// true
+	// false
+}
@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/rwxrob/cmdbox/comp"
+	"github.com/rwxrob/cmdbox/term"
 	"github.com/rwxrob/cmdbox/util"
 )
 
@@ -40,6 +41,12 @@ const (
 	m_unexpected_arg = "unexpected argument: %v"
 	m_missing_caller = "requires caller"
 	m_unresolvable   = "unsolvable command: %v"
+	m_ambiguous      = "ambiguous command: %v (%v)"
+	m_unknown_format = "unknown output format: %v"
+	m_link_conflict  = "not replacing existing files: %v"
+	m_cycle          = "command cycle: %v"
+	m_too_deep       = "command resolution too deep (max %v): %v"
+	m_not_available  = "%v is not available because %v"
 )
 
 // Main is always set to the main command that was used for Execute.
@@ -96,7 +103,8 @@ func TestOff() {
 
 // Color sets the default output mode for interactive terminals. Set to
 // false to force uncolored output for testing, etc. Non-interactive
-// terminals have color disabled by default (unless ForceColor is set).
+// terminals have color disabled by default (unless ForceColor is set)
+// as does setting the NO_COLOR environment variable to anything.
 //
 var Color = true
 
@@ -107,6 +115,47 @@ var Color = true
 //
 var ForceColor = false
 
+// colorOn returns true if color output is enabled, either because Color
+// is true, NO_COLOR is not set, and output is to an interactive terminal
+// or because ForceColor has been set.
+func colorOn() bool {
+	if ForceColor {
+		return true
+	}
+	return Color && os.Getenv("NO_COLOR") == "" && term.IsTerminal()
+}
+
+// Abbrev enables unambiguous prefix abbreviation of subcommand names
+// and aliases for every Command in the register no matter what their
+// individual Command.Abbrev value is. This is mostly useful for
+// composites used from places where tab completion is not available
+// (chat bots, remote shells without bash-completion, etc.). See
+// Command.Expand.
+//
+var Abbrev bool
+
+// Prompt enables prompting for missing arguments (see Command.Arg) for
+// every Command no matter what their individual Command.Prompt value is.
+//
+var Prompt bool
+
+// NamespaceDups enables automatic namespacing of conflicting names by
+// module when Commands are added. Instead of appending an underscore
+// (see Dups) to a name already registered from another module (see
+// Origin), the name is prefixed with the Namespace of the module and
+// a dot (so bar from github.com/someone/tools becomes tools.bar along
+// with its subcommands added afterward). Conflicts within a single
+// module are still marked with underscores.
+//
+var NamespaceDups bool
+
+// MaxDepth is the most Commands that resolution may pass through
+// (following subcommands and Defaults) before giving up with TooDeep.
+// It protects against runaway resolution that is not an outright cycle
+// (see Cycle).
+//
+var MaxDepth = 64
+
 // Reg is the internal register (map) of Commands. See CommandMap and
 // Add. Use caution when manipulating Reg directly.
 //
@@ -124,13 +173,12 @@ func JSON() string { return Reg.JSON() }
 //
 func Print() { Reg.Print() }
 
-// Init initializes (or re-initialized) the package status and empties
-// the internal commands register (without changing its reference).
+// Init initializes (or re-initialized) the package status, empties
+// the internal commands register (without changing its reference), and
+// removes any middleware added with Use.
 // Init is primarily intended for testing to reset the cmdbox package.
 //
-func Init() {
-	Reg.Init()
-}
+func Init() { Default.Init() }
 
 // Add creates a new Command, adds it to the Reg internal register, and
 // returns a pointer to it (assigned to 'x' by convention).  The Add
@@ -249,26 +297,20 @@ func Init() {
 // conflicts in advance and be able to easily correct them by calling
 // the Rename function before Execute.
 //
-func Add(name string, a ...string) *Command {
-	var x *Command
-	for {
-		x = Reg.Get(name)
-		if x == nil {
-			break
-		}
-		name = name + "_"
-	}
-	x = NewCommand(name, a...)
-	Reg.Set(name, x)
-	return x
-}
+// Add records the Origin of each Command (the package, file, and module
+// that added it) so that Conflicts can report which imported packages
+// are responsible for each duplicate. Enable NamespaceDups to have names
+// that conflict across modules prefixed by module instead.
+//
+func Add(name string, a ...string) *Command { return Default.Add(name, a...) }
 
 // Names returns a sorted list of all Command names in the internal
 // register.
 func Names() []string { return Reg.Names() }
 
 // Dups returns key strings of duplicates (which can then be easily
-// renamed). Keys are sorted in lexicographic order. See Rename.
+// renamed). Keys are sorted in lexicographic order. See Rename and
+// Conflicts.
 func Dups() []string { return Reg.Dups() }
 
 // Rename renames a Command in the Reg register by adding the
@@ -348,7 +390,8 @@ func ExitUnimplemented(a string) { ExitError(Unimplemented(a)) }
 // error as an exit message. It is used to gaurantee that no cmdbox
 // composite command will ever panic (exiting instead). It can be
 // redefined to behave differently or set to an empty func() to allow
-// the panic to blow up with its full trace log.
+// the panic to blow up with its full trace log. Boxes other than the
+// Default trap their own panics and exit with their own ExitError.
 //
 var TrapPanic = func() {
 	if r := recover(); r != nil {
@@ -422,6 +465,43 @@ var Unresolvable = func(msg string) error {
 	return fmt.Errorf(m_unresolvable, msg)
 }
 
+// Ambiguous returns an error stating the abbreviated name matches more
+// than one of the candidate commands. See Abbrev and Command.Expand.
+var Ambiguous = func(name string, candidates []string) error {
+	return fmt.Errorf(m_ambiguous, name, strings.Join(candidates, "|"))
+}
+
+// UnknownFormat returns an error stating the output format requested is
+// not one of the Formats.
+var UnknownFormat = func(format string) error {
+	return fmt.Errorf(m_unknown_format, format)
+}
+
+// Cycle returns an error naming the Commands (in order) that resolve
+// back to the first without consuming any arguments (usually Defaults
+// that point to an ancestor). See Resolve and Validate.
+var Cycle = func(names []string) error {
+	return fmt.Errorf(m_cycle, strings.Join(names, " -> "))
+}
+
+// TooDeep returns an error stating that resolution passed through more
+// than max Commands (listing them). See MaxDepth.
+var TooDeep = func(max int, names []string) error {
+	return fmt.Errorf(m_too_deep, max, strings.Join(names, " -> "))
+}
+
+// NotAvailable returns an error stating why the named Command cannot be
+// used. See Command.Available.
+var NotAvailable = func(name string, why error) error {
+	return fmt.Errorf(m_not_available, name, why)
+}
+
+// LinkConflict returns an error naming the existing files that were
+// not replaced with links to the executable. See Link.
+var LinkConflict = func(paths []string) error {
+	return fmt.Errorf(m_link_conflict, strings.Join(paths, ", "))
+}
+
 // --------------------- resolve / call / execute ---------------------
 
 // Resolve looks up a Command from the internal Reg register based on
@@ -434,11 +514,18 @@ var Unresolvable = func(msg string) error {
 //
 //   * If x.Method defined, call and return it with args unaltered
 //
+//   * If x.Handler defined, return a Method passing it the Invocation
+//
+//   * If x.Result defined, return a Method rendering it (see Render)
+//
 //   * If first arg in x.Commands, recursively Call with shifted args
 //
 //     * First with x.Name + " " + cmd
 //     * Then with just cmd
 //
+//   * If first args match a Plugin executable, run it with the rest
+//     (only when there is no Default but help or shell, see Plugin)
+//
 //   * If x.Default defined, recursively Call with shifted args
 //
 //     * First with x.Name + " " + x.Default
@@ -446,6 +533,17 @@ var Unresolvable = func(msg string) error {
 //
 //   * Return nil and args
 //
+// Resolution that would return to a Command already being resolved
+// without consuming any arguments (a cycle, usually from Defaults)
+// returns a Method that only returns the Cycle error, as does passing
+// through more than MaxDepth Commands (TooDeep). See Validate.
+//
+// When abbreviation is enabled (see Abbrev and Command.Abbrev) the
+// first argument may be any unique prefix of a subcommand name or alias.
+// An abbreviation matching more than one subcommand returns a Method
+// that only returns the Ambiguous error so that it can be reported
+// (unless the Command has a Default, which gets the argument instead).
+//
 // By convention, passing a nil as the caller indicates the Command was
 // called from something besides another Command, usually the cmdbox
 // package itself or test cases. See Call, Command, ExampleResolve for
@@ -453,65 +551,159 @@ var Unresolvable = func(msg string) error {
 //
 func Resolve(caller *Command, name string, args []string) (Method,
 	[]string) {
+	return Default.Resolve(caller, name, args)
+}
+
+// Resolve is the Box equivalent of the package Resolve function.
+func (b *Box) Resolve(caller *Command, name string, args []string) (Method,
+	[]string) {
+	inv := &Invocation{Box: b, Caller: caller, Name: name, Argv: args}
+	method, args, path, err := b.resolve(inv, caller, name, args)
+	if err != nil {
+		return func(none ...string) error { return err }, args
+	}
+	inv.setPath(path, args)
+	return method, args
+}
+
+// resolve does the work of Resolve and also returns the path of
+// Commands traversed to get to the Method (starting with the caller if
+// it qualified the name) so that hooks and middleware can be applied.
+// The Invocation is passed to any Handler resolved.
+func (b *Box) resolve(inv *Invocation, caller *Command, name string,
+	args []string) (Method, []string, []*Command, error) {
 	var x *Command
+	var path []*Command
 
 	// fully qualified, if found
 	if caller != nil {
-		full := Reg.Get(caller.Name + " " + name)
+		full := b.reg().Get(caller.Name + " " + name)
+		inv.trace.found("lookup", caller.Name+" "+name, full, args)
 		if full != nil {
 			x = full
+			path = append(path, caller)
 		}
 	}
 
 	// plain
 	if x == nil {
-		x = Reg.Get(name)
+		x = b.reg().Get(name)
+		inv.trace.found("lookup", name, x, args)
 	}
 
 	// nothing at all, we're done here
 	if x == nil {
-		return nil, args
+		return nil, args, nil, nil
+	}
+
+	// never pass through the same Command twice without progress
+	if err := inv.enter(x, len(args), b.maxDepth()); err != nil {
+		return nil, args, nil, err
+	}
+	defer inv.leave()
+
+	// build it now if added with AddLazy
+	x.load()
+
+	// refuse if it cannot be used right now
+	if err := x.Unavailable(); err != nil {
+		inv.trace.add("unavailable", x.Name, err.Error(), args)
+		return nil, args, nil, err
+	}
+
+	// warn (unless only explaining) and forward to any replacement
+	if x.Deprecated != nil {
+		inv.trace.add("deprecated", x.Name, x.Deprecated.Replacement, args)
+		if inv.trace == nil {
+			b.warnDeprecated(x)
+		}
+		if r := x.Deprecated.Replacement; r != "" {
+			return b.resolve(inv, caller, r, args)
+		}
 	}
 
-	// so that Commands know their caller
+	// deprecated, see Invocation
+	x.Lock()
 	x.Caller = caller
+	x.Unlock()
+	path = append(path, x)
 
 	// ultimately, this is where recursion stops (successfully)
 	if x.Method != nil {
-		return x.Method, args
+		inv.trace.found("method", x.Name, x, args)
+		return x.Method, args, path, nil
+	}
+	if x.Handler != nil {
+		inv.trace.found("handler", x.Name, x, args)
+		return func(args ...string) error {
+			inv.Args = args
+			return x.Handler(inv)
+		}, args, path, nil
+	}
+	if x.Result != nil {
+		inv.trace.found("result", x.Name, x, args)
+		return x.resultMethod(), args, path, nil
+	}
+
+	// delegate, prepending this path to that of the delegate
+	sub := func(name string, args []string) (Method, []string,
+		[]*Command, error) {
+		method, margs, mpath, err := b.resolve(inv, caller, name, args)
+		if method != nil {
+			mpath = append(path[:len(path):len(path)], mpath...)
+		}
+		return method, margs, mpath, err
 	}
 
-	// check if the first argument is a command with Method
+	// check if the first argument is a command with Method (ambiguity
+	// only matters without a Default to take the argument instead)
 	if len(args) > 0 {
-		first := args[0]
-		if cmd := x.Commands.Get(first); cmd != "" {
+		cmd, err := x.Expand(args[0])
+		if err != nil {
+			inv.trace.add("expand", args[0], err.Error(), args)
+			if x.Default == "" {
+				return nil, args, nil, err
+			}
+		} else {
+			inv.trace.add("expand", args[0], cmd, args)
+		}
+		if cmd != "" {
 			name = name + " " + cmd
-			method, margs := Resolve(caller, name, args[1:])
-			if method != nil {
-				return method, margs
+			method, margs, mpath, err := sub(name, args[1:])
+			if method != nil || err != nil {
+				return method, margs, mpath, err
 			}
-			method, margs = Resolve(caller, cmd, args[1:])
-			if method != nil {
-				return method, margs
+			method, margs, mpath, err = sub(cmd, args[1:])
+			if method != nil || err != nil {
+				return method, margs, mpath, err
 			}
 		}
 	}
 
+	// check for an external plugin executable (never with a Default but help or shell)
+	if len(args) > 0 {
+		if p, rest := x.LookPlugin(args); p != nil {
+			inv.trace.add("plugin", p.Name, p.Path, rest)
+			return p.Method(), rest, path, nil
+		}
+	}
+
 	// check for default command with method
 	if x.Default != "" {
+		inv.trace.add("default", x.Name, x.Default, args)
 		name = name + " " + x.Default
-		method, margs := Resolve(caller, name, args)
-		if method != nil {
-			return method, margs
+		method, margs, mpath, err := sub(name, args)
+		if method != nil || err != nil {
+			return method, margs, mpath, err
 		}
-		method, margs = Resolve(caller, x.Default, args)
-		if method != nil {
-			return method, margs
+		method, margs, mpath, err = sub(x.Default, args)
+		if method != nil || err != nil {
+			return method, margs, mpath, err
 		}
 	}
 
 	// out of options
-	return nil, args
+	return nil, args, nil, nil
 }
 
 // Call allows any Command in the internal register to be called
@@ -520,14 +712,34 @@ func Resolve(caller *Command, name string, args []string) (Method,
 // optional list of string arguments (or nil). Resolve is first called
 // to get the Command from the internal registry and lookup the proper
 // Method and any argument shifting required. If no Method is returned
-// Call returns Unimplemented. Otherwise, Method is called with its
-// arguments and error result returned.  See command.Call, Resolve,
+// Call returns Unimplemented. Otherwise, Method is wrapped with any
+// middleware and hooks (see Use, Command.Middleware, Command.Pre, and
+// Command.Post) and called with its arguments and error result
+// returned.  See command.Call, Resolve,
 // Command, Execute, and ExampleCall as well.
 //
 func Call(caller *Command, name string, args ...string) error {
-	defer TrapPanic()
+	return Default.Call(caller, name, args...)
+}
+
+// Call is the Box equivalent of the package Call function.
+func (b *Box) Call(caller *Command, name string, args ...string) error {
+	return b.invoke(&Invocation{Caller: caller, Name: name, Argv: args})
+}
+
+// invoke does the work of Call for the Invocation (which must have its
+// Caller, Name, and Argv set).
+func (b *Box) invoke(inv *Invocation) error {
+	if b == Default {
+		defer TrapPanic()
+	} else {
+		defer b.trapPanic()
+	}
+
+	inv.Box = b
+	caller, name, args := inv.Caller, inv.Name, inv.Argv
 
-	if DEBUG {
+	if b.debug() {
 		out := fmt.Sprintf("CALLING: %v(%q)", name, args)
 		if caller != nil {
 			out += " from " + caller.Name
@@ -539,14 +751,18 @@ func Call(caller *Command, name string, args ...string) error {
 		return MissingArg("name")
 	}
 
-	method, args := Resolve(caller, name, args)
+	method, args, path, err := b.resolve(inv, caller, name, args)
+	if err != nil {
+		return err
+	}
 	if method == nil {
 		if caller != nil {
 			return caller.UsageError()
 		}
-		return Unresolvable(fmt.Sprintf("%v(%q)", name, args))
+		return b.unresolvable(fmt.Sprintf("%v(%q)", name, args))
 	}
-	return method(args...)
+	inv.setPath(path, args)
+	return wrap(inv, method)(args...)
 }
 
 // ExecutedAs returns the multicall inferred name of the executable as
@@ -562,14 +778,47 @@ func ExecutedAs() string { return executedAs }
 
 var executedAs = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
 
+// MulticallName returns the register name for the name of an executable
+// (see ExecutedAs) allowing links to subcommands to be called as
+// standalone programs. If the name is in the register it is returned
+// as is. Otherwise, the first of the following found in the register is
+// returned:
+//
+//   * dashes replaced with spaces (foo-bar as "foo bar")
+//   * dots replaced with spaces (foo.bar as "foo bar")
+//   * both replaced with spaces (foo-bar.baz as "foo bar baz")
+//
+// If none are found the name is returned unchanged.
+//
+func MulticallName(name string) string { return Default.MulticallName(name) }
+
+// MulticallName is the Box equivalent of the package MulticallName
+// function.
+func (b *Box) MulticallName(name string) string {
+	if b.reg().Get(name) != nil {
+		return name
+	}
+	dashes := strings.ReplaceAll(name, "-", " ")
+	dots := strings.ReplaceAll(name, ".", " ")
+	both := strings.ReplaceAll(dashes, ".", " ")
+	for _, n := range []string{dashes, dots, both} {
+		if n != name && b.reg().Get(n) != nil {
+			return n
+		}
+	}
+	return name
+}
+
 // only call when DEBUG true
-func checkSyntax(a []string) {
+func (b *Box) checkSyntax(a []string) {
 	util.Log("ARGUMENTS -----------------------------------------")
 	util.Log(a)
 	util.Log("NAMES ---------------------------------------------")
-	util.Log(Names())
+	util.Log(b.Names())
 	util.Log("DUPLICATES ----------------------------------------")
-	util.Log(Dups())
+	util.Log(b.Conflicts())
+	util.Log("CYCLES --------------------------------------------")
+	util.Log(b.Validate())
 	//util.Log("MISSING OWNER -------------------------------------")
 	// TODO iterate through all commands in Reg and check that each
 	// is in a command list of one of the other commands.
@@ -577,13 +826,13 @@ func checkSyntax(a []string) {
 	// TODO iterate through all the commands of each entry in Reg
 	// and attempt to resolve a method for it. List only those that
 	// fail to resolve.
-	dumpReg()
+	b.dumpReg()
 }
 
 // only call when DEBUG true
-func dumpReg() {
+func (b *Box) dumpReg() {
 	util.Log("REGISTER (Reg) ------------------------------------")
-	util.Log(Reg)
+	util.Log(b.reg())
 	util.Log("---------------------------------------------------")
 }
 
@@ -597,16 +846,25 @@ func dumpReg() {
 //     func main() { cmdbox.Execute() }
 //
 // Execute also traps all panics and eventually Calls the Command
-// matching the inferred name from the Reg Commands register. If
+// matching the inferred name from the Reg Commands register (see
+// MulticallName for how foo-bar and foo.bar reach "foo bar"). If
 // completion context is detected (see comp.Yes), Execute calls
-// x.Complete instead of Calling it. Execute is guaranteed to always
+// x.Complete instead of Calling it. The register is frozen (see
+// CommandMap.Freeze) before the call. Execute is guaranteed to always
 // exit the program cleanly. See Call, TrapPanic, and Command.
 //
-func Execute(a ...string) {
-	defer TrapPanic()
+func Execute(a ...string) { Default.Execute(a...) }
 
-	if DEBUG {
-		checkSyntax(a)
+// Execute is the Box equivalent of the package Execute function.
+func (b *Box) Execute(a ...string) {
+	if b == Default {
+		defer TrapPanic()
+	} else {
+		defer b.trapPanic()
+	}
+
+	if b.debug() {
+		b.checkSyntax(a)
 	}
 
 	// infer the name
@@ -617,25 +875,27 @@ func Execute(a ...string) {
 		name = executedAs
 	}
 
-	if DEBUG {
-		util.Log("INFERRED NAME: " + name)
-	}
+	b.debugLog("INFERRED NAME: " + name)
 
-	// check name and set as Main
-	x := Reg.Get(name)
+	// check name (or multicall equivalent) and set as Main
+	name = b.MulticallName(name)
+	x := b.reg().Get(name)
 	if x == nil {
-		if DEBUG {
-			util.Log("WARNING: " + name + " not found in registry")
-		}
-		ExitUnimplemented(name)
+		b.debugLog("WARNING: " + name + " not found in registry")
+		b.ExitError(b.unimplemented(name))
+		return
 	}
 
-	Main = x
+	x.load()
+	b.setMain(x)
 
 	x.UpdateUsage()
 
-	if DEBUG {
-		dumpReg()
+	// registration is done, so lookups need no locking from here on
+	b.reg().Freeze()
+
+	if b.debug() {
+		b.dumpReg()
 		if x.CommandRequired() {
 			util.Log("WARNING: no default subcommand found for " + name)
 		}
@@ -644,18 +904,19 @@ func Execute(a ...string) {
 	// detect completion context
 	if comp.Yes() {
 		x.Complete()
-		Exit()
+		b.Exit()
+		return
 	}
 
 	// otherwise, call it
 	err := x.Call(name, os.Args[1:]...)
 	if err != nil {
-		if DEBUG {
+		if b.debug() {
 			out := fmt.Sprintf("FATAL: %v(%q)", name, os.Args[1:])
 			util.Log([]interface{}{out, err})
 		}
-		ExitError(err)
+		b.ExitError(err)
 	}
 
-	Exit()
+	b.Exit()
 }
@@ -47,7 +47,12 @@ func ExampleJSON() {
 	// {
 	//     "foo": {
 	//       "name": "foo",
-	//       "commands": {}
+	//       "commands": {},
+	//       "origin": {
+	//         "package": "github.com/rwxrob/cmdbox_test",
+	//         "file": "cmdbox_test.go",
+	//         "module": "github.com/rwxrob/cmdbox"
+	//       }
 	//     }
 	//   }
 
@@ -143,6 +148,11 @@ func ExampleGet() {
 	//     "usage": "bar",
 	//     "commands": {
 	//       "bar": "bar"
+	//     },
+	//     "origin": {
+	//       "package": "github.com/rwxrob/cmdbox_test",
+	//       "file": "cmdbox_test.go",
+	//       "module": "github.com/rwxrob/cmdbox"
 	//     }
 	//   }
 
@@ -299,3 +309,91 @@ func ExampleExecute_no_Method() {
 	// usage: foo some
 
 }
+
+func ExampleCall_abbrev() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "status", "stop", "l|list")
+	x.Abbrev = true
+
+	for _, name := range []string{"status", "stop", "list"} {
+		name := name
+		c := cmdbox.Add("foo " + name)
+		c.Method = func(args ...string) error {
+			fmt.Println(name, args)
+			return nil
+		}
+	}
+
+	cmdbox.Call(nil, "foo", "sta", "now")
+	cmdbox.Call(nil, "foo", "sto")
+	cmdbox.Call(nil, "foo", "l")
+	cmdbox.Call(nil, "foo", "li")
+	fmt.Println(cmdbox.Call(nil, "foo", "st"))
+
+	x.Default = "list"
+	cmdbox.Call(nil, "foo", "st")
+	x.Default = ""
+
+	// only listed subcommands are candidates
+	cmdbox.Get("foo stop").Deprecated = &cmdbox.Deprecation{}
+	cmdbox.Call(nil, "foo", "st")
+	cmdbox.Get("foo status").Available = cmdbox.NeedsEnv("CMDBOX_NEVER_SET")
+	fmt.Println(cmdbox.Call(nil, "foo", "sta"))
+
+	// Output:
+	// status [now]
+	// stop []
+	// list []
+	// list []
+	// ambiguous command: st (status|stop)
+	// list [st]
+	// status []
+	// unsolvable command: foo(["sta"])
+
+}
+
+func ExampleMulticallName() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	cmdbox.Add("foo", "bar")
+	cmdbox.Add("foo bar", "baz")
+	cmdbox.Add("foo bar baz")
+	cmdbox.Add("dotted.name")
+
+	fmt.Printf("%q\n", cmdbox.MulticallName("foo"))
+	fmt.Printf("%q\n", cmdbox.MulticallName("foo-bar"))
+	fmt.Printf("%q\n", cmdbox.MulticallName("foo.bar"))
+	fmt.Printf("%q\n", cmdbox.MulticallName("foo-bar.baz"))
+	fmt.Printf("%q\n", cmdbox.MulticallName("dotted.name"))
+	fmt.Printf("%q\n", cmdbox.MulticallName("foo-nope"))
+
+	// Output:
+	// "foo"
+	// "foo bar"
+	// "foo bar"
+	// "foo bar baz"
+	// "dotted.name"
+	// "foo-nope"
+}
+
+func ExampleExecute_multicall() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	cmdbox.ExitOff()
+	defer cmdbox.ExitOn()
+
+	cmdbox.Add("foo", "bar")
+	x := cmdbox.Add("foo bar")
+	x.Method = func(args ...string) error {
+		fmt.Println("foo bar called")
+		return nil
+	}
+
+	cmdbox.Execute("foo-bar")
+
+	// Output:
+	// foo bar called
+}
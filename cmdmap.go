@@ -24,18 +24,39 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/rwxrob/cmdbox/util"
 )
 
-// CommandMap encapsulates a map[string]*Command and embeds a sync.Mutex
-// for locking making it safe for concurrency. The internal map is
-// exported (M) in the event developers want more direct control without
-// using the CommandMap methods that ensure concurrency safety. See the
-// Register function for more.
+// CommandMap encapsulates a map[string]*Command and embeds
+// a sync.RWMutex for locking making it safe for concurrency (with any
+// number of concurrent lookups). The internal map is exported (M) in the
+// event developers want more direct control without using the
+// CommandMap methods that ensure concurrency safety. See the Register
+// function for more.
+//
+// Frozen
+//
+// Since the register is rarely changed once init() is done a CommandMap
+// may be frozen (see Freeze), after which every lookup uses an
+// immutable snapshot without any locking at all. Execute freezes the
+// register it uses. Changes made with the CommandMap methods after
+// freezing are still safe (and take effect immediately) but are slower
+// since each replaces the entire snapshot. Direct changes to M are
+// never seen by a frozen CommandMap until it is frozen again.
+//
 type CommandMap struct {
 	M map[string]*Command
-	sync.Mutex
+	sync.RWMutex
+	snap atomic.Value // *snapshot
+}
+
+// snapshot is an immutable copy of the internal map. A nil *snapshot
+// means the CommandMap is not frozen.
+type snapshot struct {
+	m     map[string]*Command
+	names []string
 }
 
 // NewCommandMap returns a new CommandMap with the internal
@@ -46,29 +67,74 @@ func NewCommandMap() *CommandMap {
 	return m
 }
 
+// frozen returns the current snapshot or nil if not frozen.
+func (m *CommandMap) frozen() *snapshot {
+	s, _ := m.snap.Load().(*snapshot)
+	return s
+}
+
+// Freeze takes an immutable snapshot of the internal map that is used
+// for all lookups without any locking. See Frozen.
+//
+func (m *CommandMap) Freeze() {
+	m.Lock()
+	defer m.Unlock()
+	m.freeze()
+}
+
+// freeze must be called with the lock held.
+func (m *CommandMap) freeze() {
+	s := &snapshot{m: make(map[string]*Command, len(m.M))}
+	for k, v := range m.M {
+		s.m[k] = v
+		s.names = append(s.names, k)
+	}
+	sort.Strings(s.names)
+	m.snap.Store(s)
+}
+
+// refreeze replaces the snapshot after a change (if frozen) and must be
+// called with the lock held.
+func (m *CommandMap) refreeze() {
+	if m.frozen() != nil {
+		m.freeze()
+	}
+}
+
+// Thaw discards the frozen snapshot (if any) returning to locked
+// lookups of the internal map.
+//
+func (m *CommandMap) Thaw() { m.snap.Store((*snapshot)(nil)) }
+
+// Frozen returns true if the CommandMap has been frozen (see Freeze).
+func (m *CommandMap) Frozen() bool { return m.frozen() != nil }
+
 // Set set a value by key name safe in a way that is for concurrency.
 func (m *CommandMap) Set(key string, val *Command) {
-	defer m.Unlock()
 	m.Lock()
+	defer m.Unlock()
 	m.M[key] = val
+	m.refreeze()
 }
 
 // Get returns a Command pointer by key name safe for concurrency.
 // Returns nil if not found.
 func (m *CommandMap) Get(key string) *Command {
-	m.Lock()
-	defer m.Unlock()
-	if v, has := m.M[key]; has {
-		return v
+	if s := m.frozen(); s != nil {
+		return s.m[key]
 	}
-	return nil
+	m.RLock()
+	defer m.RUnlock()
+	return m.M[key]
 }
 
 // Init initializes (or re-initialized) the CommandMap deleting all its
-// values (without changing its reference).
+// values (without changing its reference) and discarding any frozen
+// snapshot (see Thaw).
 func (m *CommandMap) Init() {
 	m.Lock()
 	defer m.Unlock()
+	m.Thaw()
 	if m.M == nil {
 		m.M = make(map[string]*Command)
 		return
@@ -81,11 +147,12 @@ func (m *CommandMap) Init() {
 // Delete removes one or more entries from the map in a way that is safe
 // for concurrency.
 func (m *CommandMap) Delete(keys ...string) {
-	defer m.Unlock()
 	m.Lock()
+	defer m.Unlock()
 	for _, k := range keys {
 		delete(m.M, k)
 	}
+	m.refreeze()
 }
 
 // Rename renames a Command in the Register by adding the
@@ -96,9 +163,9 @@ func (m *CommandMap) Delete(keys ...string) {
 // Note the order of init() execution --- while predictable --- is not
 // always apparent.  When in doubt do Rename from main() to be sure.
 // Rename is safe for concurrency.
-func (m CommandMap) Rename(from, to string) {
-	defer m.Unlock()
+func (m *CommandMap) Rename(from, to string) {
 	m.Lock()
+	defer m.Unlock()
 	x, has := m.M[from]
 	if !has {
 		return
@@ -106,19 +173,23 @@ func (m CommandMap) Rename(from, to string) {
 	x.Name = to
 	m.M[to] = x
 	delete(m.M, from)
+	m.refreeze()
 }
 
 // ------------------------------ queries -----------------------------
 
 // Names returns a sorted list of all Command names.
-func (m CommandMap) Names() []string {
-	m.Lock()
-	defer m.Unlock()
-	keys := make([]string, len(m.M))
-	var i int
-	for k, _ := range m.M {
-		keys[i] = k
-		i++
+func (m *CommandMap) Names() []string {
+	if s := m.frozen(); s != nil {
+		keys := make([]string, len(s.names))
+		copy(keys, s.names)
+		return keys
+	}
+	m.RLock()
+	defer m.RUnlock()
+	keys := make([]string, 0, len(m.M))
+	for k := range m.M {
+		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 	return keys
@@ -126,16 +197,13 @@ func (m CommandMap) Names() []string {
 
 // Dups returns key strings of duplicates (which can then be easily
 // renamed). Keys are sorted in lexicographic order. See Rename.
-func (m CommandMap) Dups() []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *CommandMap) Dups() []string {
 	var keys []string
-	for k, _ := range m.M {
+	for _, k := range m.Names() {
 		if k[len(k)-1] == '_' {
 			keys = append(keys, k)
 		}
 	}
-	sort.Strings(keys)
 	return keys
 }
 
@@ -143,12 +211,10 @@ func (m CommandMap) Dups() []string {
 // internal register that match the key names passed.  If an entry is
 // not found it is simply skipped. Will return an empty slice if none
 // found.
-func (m CommandMap) Slice(names ...string) []*Command {
-	defer m.Unlock()
-	m.Lock()
+func (m *CommandMap) Slice(names ...string) []*Command {
 	cmds := []*Command{}
 	for _, name := range names {
-		if x, has := m.M[name]; has {
+		if x := m.Get(name); x != nil {
 			cmds = append(cmds, x)
 		}
 	}
@@ -158,16 +224,24 @@ func (m CommandMap) Slice(names ...string) []*Command {
 // ---------------------------- marshaling ----------------------------
 
 // RawJSON calls MustRawJSON on the internal map.
-func (m CommandMap) RawJSON() string { return util.MustRawJSON(m.M) }
+func (m *CommandMap) RawJSON() string {
+	m.RLock()
+	defer m.RUnlock()
+	return util.MustRawJSON(m.M)
+}
 
 // JSON calls util.MustJSON on the internal map. It is often more
 // convenient to simply print/Print instead since the String (from
 // fmt.Stringer interface) does the same thing.
 //
-func (m CommandMap) JSON() string { return util.MustJSON(m.M) }
+func (m *CommandMap) JSON() string {
+	m.RLock()
+	defer m.RUnlock()
+	return util.MustJSON(m.M)
+}
 
 // String fulfills fmt.Stringer interface as JSON.
-func (m CommandMap) String() string { return util.MustJSON(m.M) }
+func (m *CommandMap) String() string { return m.JSON() }
 
 // Print outputs as JSON (nice when testing).
-func (m CommandMap) Print() { fmt.Println(util.MustJSON(m.M)) }
+func (m *CommandMap) Print() { fmt.Println(m.JSON()) }
@@ -2,6 +2,7 @@ package cmdbox_test
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/rwxrob/cmdbox"
 )
@@ -190,3 +191,77 @@ func ExampleCommandMap_String() {
 	//     }
 	//   }
 }
+
+func ExampleCommandMap_Freeze() {
+	m := cmdbox.NewCommandMap()
+	m.Set("foo", cmdbox.NewCommand("foo"))
+	m.Freeze()
+	fmt.Println(m.Frozen(), m.Names())
+	m.Set("bar", cmdbox.NewCommand("bar"))
+	fmt.Println(m.Frozen(), m.Names(), m.Get("bar").Name)
+	m.Thaw()
+	fmt.Println(m.Frozen(), m.Names())
+
+	// Output:
+	// true [foo]
+	// true [bar foo] bar
+	// false [bar foo]
+}
+
+// bigMap returns a CommandMap with a composite of n subcommands.
+func bigMap(n int) *cmdbox.CommandMap {
+	cmdbox.Init()
+	names := make([]string, n)
+	for i := range names {
+		// names must be words, so base 26 with letters
+		name := ""
+		for n := i; ; n /= 26 {
+			name = string(rune('a'+n%26)) + name
+			if n < 26 {
+				break
+			}
+		}
+		names[i] = "sub" + name
+	}
+	cmdbox.Add("big", names...)
+	for _, name := range names {
+		x := cmdbox.Add("big " + name)
+		x.Method = func(args ...string) error { return nil }
+	}
+	return cmdbox.Reg
+}
+
+func benchmarkGet(b *testing.B, frozen bool) {
+	m := bigMap(1000)
+	defer cmdbox.Init()
+	if frozen {
+		m.Freeze()
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.Get("big subtg")
+		}
+	})
+}
+
+func BenchmarkCommandMap_Get(b *testing.B)        { benchmarkGet(b, false) }
+func BenchmarkCommandMap_Get_frozen(b *testing.B) { benchmarkGet(b, true) }
+
+func benchmarkResolve(b *testing.B, frozen bool) {
+	m := bigMap(1000)
+	defer cmdbox.Init()
+	if frozen {
+		m.Freeze()
+	}
+	args := []string{"subtg", "some", "args"}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cmdbox.Resolve(nil, "big", args)
+		}
+	})
+}
+
+func BenchmarkResolve(b *testing.B)        { benchmarkResolve(b, false) }
+func BenchmarkResolve_frozen(b *testing.B) { benchmarkResolve(b, true) }
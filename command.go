@@ -18,9 +18,11 @@ package cmdbox
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/rwxrob/cmdbox/term"
 	"github.com/rwxrob/cmdbox/util"
 	"github.com/rwxrob/cmdbox/valid"
 )
@@ -137,6 +139,95 @@ import (
 // up in any general help documentation. They have to be specifically
 // used or passed to help directly.
 //
+// Abbrev
+//
+// When Abbrev is true (or the package cmdbox.Abbrev is set) any unique
+// prefix of a subcommand name or alias is accepted in place of the full
+// name (see Expand). Hidden commands are never matched by abbreviation
+// and must always be typed in full.
+//
+// Prompt
+//
+// When Prompt is true (or the package cmdbox.Prompt is set) arguments
+// fetched with Arg that are missing are prompted for interactively
+// instead of returning MissingArg (see Arg).
+//
+// Result and Format
+//
+// Instead of a Method that prints human text directly, a Command may
+// assign a Result that returns structured data which cmdbox renders as
+// text for humans on a terminal and as JSON when piped, or in whatever
+// format the user requests with CMDBOX_FORMAT or a reserved trailing
+// word (see OutputFormat and Render). Format sets the default format
+// for the Command whether or not output is to a terminal (overriding
+// both text and JSON). A Method always takes priority over a Result.
+//
+//    x.Result = func(args ...string) (interface{}, error) {
+//      return map[string]int{"open": 3, "closed": 12}, nil
+//    }
+//
+// Pre, Post, and Middleware
+//
+// The Pre and Post Hooks run before and after the Method of the Command
+// and of every descendant Command resolved through it (see Call). Pre
+// hooks run from the outermost Command inward and Post hooks from the
+// innermost outward. Middleware wraps the resolved Method of the
+// Command and its descendants inside of any global Middleware (see
+// Use).
+//
+//    x.Pre = func(inv *cmdbox.Invocation, _ error) error {
+//      if os.Getenv("TOKEN") == "" {
+//        return fmt.Errorf("%v requires TOKEN", inv.Command.Name)
+//      }
+//      return nil
+//    }
+//
+// Handler and Caller
+//
+// A Handler may be assigned instead of a Method to be passed the
+// Invocation with everything about the specific call (the caller chain,
+// resolved path, original arguments, and per-call values, see
+// Invocation). A Method takes priority over a Handler, which takes
+// priority over a Result.
+//
+//    x.Handler = func(inv *cmdbox.Invocation) error {
+//      fmt.Println("called from", inv.Caller.Name, "with", inv.Args)
+//      return nil
+//    }
+//
+// Only a Handler is passed the Invocation. A Method has no way to reach
+// it, so use a Handler whenever the caller chain, original arguments,
+// or per-call values are needed.
+//
+// Caller is deprecated and kept only for compatibility. It is set (under
+// the lock of the Command) to the most recent caller during resolution,
+// which is unreliable when the same Command is called concurrently. Use
+// a Handler and Invocation instead.
+//
+// Available
+//
+// An Available function may be assigned to hide a Command from help and
+// completion whenever it cannot be used (a required executable, file,
+// user, or environment variable is missing, see NeedsExec and such).
+// Calling it anyway returns the NotAvailable error with the reason.
+// Available is only evaluated when needed. (For Commands added with
+// AddLazy it must be set before they are built, see AddLazy.)
+//
+//    x.Available = cmdbox.NeedsExec("kubectl")
+//
+// Deprecated and Stability
+//
+// A Command that is going away (usually renamed) can be marked
+// Deprecated so that calling it prints a warning to standard error
+// (once) and forwards the call to its Replacement (if any). Deprecated
+// Commands are marked in help and omitted from completion. Stability
+// notes whether a Command is Experimental or Stable (marking the former
+// in help). Both are included in JSON. (Like Available, both must be set
+// before a Command added with AddLazy is built, see AddLazy.)
+//
+//    x.Deprecated = &cmdbox.Deprecation{Replacement: "foo list",
+//      Removal: "v2.0.0"}
+//
 // Examples
 //
 // For examples of different Command structs search on GitHub for any
@@ -160,17 +251,32 @@ type Command struct {
 	Params      []string        `json:"params,omitempty" yaml:",omitempty"`
 	Hidden      []string        `json:"hidden,omitempty" yaml:",omitempty"`
 	Default     string          `json:"default,omitempty" yaml:",omitempty"`
+	Abbrev      bool            `json:"abbrev,omitempty" yaml:",omitempty"`
+	Prompt      bool            `json:"prompt,omitempty" yaml:",omitempty"`
+	Format      string          `json:"format,omitempty" yaml:",omitempty"`
+	Origin      *Origin         `json:"origin,omitempty" yaml:",omitempty"`
+	Deprecated  *Deprecation    `json:"deprecated,omitempty" yaml:",omitempty"`
+	Stability   Stability       `json:"stability,omitempty" yaml:",omitempty"`
 	// Title()
 	// Legal()
-	CompFunc   CompFunc `json:"-" yaml:"-"`
-	Caller     *Command `json:"-" yaml:"-"`
-	Method     Method   `json:"-" yaml:"-"`
+	CompFunc   CompFunc     `json:"-" yaml:"-"`
+	Caller     *Command     `json:"-" yaml:"-"`
+	Method     Method       `json:"-" yaml:"-"`
+	Handler    Handler      `json:"-" yaml:"-"`
+	Available  Available    `json:"-" yaml:"-"`
+	Result     Result       `json:"-" yaml:"-"`
+	Pre        Hook         `json:"-" yaml:"-"`
+	Post       Hook         `json:"-" yaml:"-"`
+	Middleware []Middleware `json:"-" yaml:"-"`
 	sync.Mutex `json:"-" yaml:"-"`
+	box        *Box
+	lazy       *lazy
+	warned     sync.Once
 }
 
 // Method represents a function to be used as Command.Method values.
 // By convention, use "args" when arguments are expected and "none" when
-// not.
+// not. Unlike a Handler, a Method is not passed the Invocation.
 type Method func(args ...string) error
 
 // NewCommand returns pointer to new initialized Command. See the New
@@ -182,11 +288,18 @@ type Method func(args ...string) error
 // Since calling NewCommand involves critical syntax checks a panic is
 // thrown if invalid.
 func NewCommand(name string, a ...string) *Command {
+	return newCommand(nil, name, a...)
+}
+
+// newCommand does the work of NewCommand associating the Command with
+// the Box (if not nil) first so that syntax errors exit through it.
+func newCommand(b *Box, name string, a ...string) *Command {
 	x := new(Command)
+	x.box = b
 
 	// exit if invalid command and not dup
 	if !valid.Name(name) && name[len(name)-1] != '_' {
-		ExitSyntaxError(fmt.Sprintf(m_invalid_name, name))
+		x.Box().ExitError(SyntaxError(fmt.Sprintf(m_invalid_name, name)))
 	}
 
 	x.Name = name
@@ -288,18 +401,59 @@ func (x *Command) Add(sigs ...string) {
 		aliases := strings.Split(sig, "|")
 		name := aliases[len(aliases)-1]
 		if !valid.Name(name) {
-			ExitSyntaxError(fmt.Sprintf(m_invalid_name, name))
+			x.Box().ExitError(SyntaxError(fmt.Sprintf(m_invalid_name, name)))
 		}
 		x.Commands.Set(name, name)
 		for _, alias := range aliases {
 			if !valid.Name(alias) {
-				ExitSyntaxError(fmt.Sprintf(m_invalid_name, name))
+				x.Box().ExitError(SyntaxError(fmt.Sprintf(m_invalid_name, name)))
 			}
 			x.Commands.Set(alias, name)
 		}
 	}
 }
 
+// Expand returns the name of the subcommand (the x.Commands value) for
+// the word passed. If word is not a subcommand name or alias and
+// abbreviation is enabled (see Abbrev) the word is treated as a prefix
+// and the single matching subcommand name is returned. If the prefix
+// matches more than one subcommand an Ambiguous error listing the
+// candidates is returned instead. Only the subcommands that would be
+// completed are candidates (never those hidden, deprecated, or not
+// currently available) although their full names still work. An empty
+// string is returned when nothing matches at all.
+//
+func (x *Command) Expand(word string) (string, error) {
+	x.load()
+	if cmd := x.Commands.Get(word); cmd != "" {
+		return cmd, nil
+	}
+	if !(x.Abbrev || x.Box().abbrev()) || word == "" {
+		return "", nil
+	}
+	candidates := []string{}
+	for _, key := range x.Commands.Keys() {
+		if !strings.HasPrefix(key, word) {
+			continue
+		}
+		cmd := x.Commands.Get(key)
+		if util.InSlice(cmd, x.Hidden) || util.InSlice(cmd, candidates) {
+			continue
+		}
+		candidates = append(candidates, cmd)
+	}
+	candidates = omitUnlisted(x, candidates)
+	switch len(candidates) {
+	case 0:
+		return "", nil
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+		return "", Ambiguous(word, candidates)
+	}
+}
+
 // Complete prints the possible strings based on the current Command and
 // completion context. If the Commands CompFunc has been assigned (not
 // nil) it is called and passed its own pointer. If CompFunc has not
@@ -307,16 +461,21 @@ func (x *Command) Add(sigs ...string) {
 // This allows Command authors to control their own completion or simply
 // use the default. It also allows changing the default by assigning to
 // the package cmdbox.DefaultComplete before calling cmdbox.Execute.
+// Completion for a Plugin is delegated to the plugin itself (see
+// Plugin.Complete).
 func (x *Command) Complete() {
-	matches := []string{}
+	x.load()
+	matches, delegated := x.completePlugin()
 	switch {
+	case delegated:
 	case x.CompFunc != nil:
 		matches = x.CompFunc(x)
 	case DefaultComplete != nil:
 		matches = DefaultComplete(x)
 	}
+	out := x.Box().stdout()
 	for _, m := range matches {
-		fmt.Println(m)
+		fmt.Fprintln(out, m)
 	}
 }
 
@@ -324,17 +483,44 @@ func (x *Command) Complete() {
 
 // Unimplemented is a convenience method that delegates calls to
 // cmdbox.Unimplemented.
-func (x *Command) Unimplemented(a string) error { return Unimplemented(a) }
+func (x *Command) Unimplemented(a string) error {
+	return x.Box().unimplemented(a)
+}
 
 // UsageError is a convenience method that delegates calls to
 // cmdbox.UsageError.
-func (x *Command) UsageError() error { return UsageError(x) }
+func (x *Command) UsageError() error { return x.Box().usageError(x) }
 
 // MissingArg returns cmdbox.MissingArg
 func (x *Command) MissingArg(a string) error {
 	return MissingArg(a)
 }
 
+// Arg returns args[i] if it exists. Otherwise, if prompting is enabled
+// (see Command.Prompt and cmdbox.Prompt) and standard input is an
+// interactive terminal, the user is prompted for it by name (see
+// term.Input) and must enter something. Otherwise, the MissingArg error
+// for the name is returned.
+//
+//    x.Method = func(args ...string) error {
+//      name, err := x.Arg(args, 0, "name")
+//      if err != nil {
+//        return err
+//      }
+//      fmt.Println("hello", name)
+//      return nil
+//    }
+//
+func (x *Command) Arg(args []string, i int, name string) (string, error) {
+	if i < len(args) {
+		return args[i], nil
+	}
+	if (x.Prompt || x.Box().prompt()) && term.IsInputTerminal() {
+		return term.Input(name+": ", term.NotEmpty)
+	}
+	return "", x.MissingArg(name)
+}
+
 // UnexpectedArg returns cmdbox.UnexpectedArg
 func (x *Command) UnexpectedArg(a string) error {
 	return UnexpectedArg(a)
@@ -342,40 +528,86 @@ func (x *Command) UnexpectedArg(a string) error {
 
 // ------------------------------ help -------------------------------
 
+// HelpMinWidth and HelpMaxWidth are the bounds within which the width
+// of the terminal (see term.Width) is kept when laying out help
+// documentation (see HelpWidth). Help gets hard to read when wrapped too
+// narrowly or stretched across very wide terminals.
+var (
+	HelpMinWidth = 40
+	HelpMaxWidth = 100
+)
+
+// HelpWidth returns the current width of the terminal (see term.Width,
+// which observes COLUMNS and window resizes) kept within HelpMinWidth
+// and HelpMaxWidth.
+func HelpWidth() int {
+	width := term.Width()
+	if width < HelpMinWidth {
+		return HelpMinWidth
+	}
+	if width > HelpMaxWidth {
+		return HelpMaxWidth
+	}
+	return width
+}
+
 // Help returns a formatted string suitable for printing either to
 // a file or to an interactive terminal. For a more structured form of
-// the same information see YAML, JSON, Print, and PrintHelp.
+// the same information see YAML, JSON, Print, and PrintHelp. The
+// Description is wrapped and the subcommand summaries are truncated to
+// fit within the HelpWidth. Headers, names, usage, and links are styled
+// according to the HelpTheme (or Theme of the Box). Links (and any <https://...> URLs in the
+// Description) are clickable on terminals that support hyperlinks (see
+// util.Hyperlinks).
 //
 func (x *Command) Help() string {
+	x.load()
 	var buf string
+	width := HelpWidth()
+	t := x.Box().theme()
+	head := func(h string) string { return x.paint(t.Header, h) + "\n" }
+	name := x.paint(t.Name, x.Name)
+
+	buf += head("NAME") + "       " + name +
+		strings.TrimPrefix(x.Title(), x.Name) + "\n\n"
+	buf += head("SYNOPSIS") + "       " + name + " " +
+		x.paint(t.Usage, x.Usage) + "\n\n"
+
+	if x.Deprecated != nil {
+		buf += head("DEPRECATED") +
+			x.emph(x.Deprecation(), 7, width-15) + "\n\n"
+	}
 
-	buf += util.Emph("**NAME**", 0, -1) + "\n       " + x.Title() + "\n\n"
-	buf += util.Emph("**SYNOPSIS**", 0, -1) + "\n       " + x.Name + " " + x.Usage + "\n\n"
+	if x.Stability != "" {
+		buf += head("STABILITY") + "       " + string(x.Stability) + "\n\n"
+	}
 
 	if len(x.Commands.M) > 0 {
-		buf += util.Emph("**COMMANDS**", 0, -1) + "\n" + x.Titles(7, 20) + "\n\n"
+		buf += head("COMMANDS") + x.Titles(7, width/4) + "\n\n"
+	}
+
+	if plugins := x.pluginTitles(7); plugins != "" {
+		buf += head("PLUGINS") + plugins + "\n\n"
 	}
 
 	if len(x.Description) > 0 {
-		buf +=
-			util.Emph("**DESCRIPTION**", 0, -1) + "\n" +
-				util.Emph(x.Description, 7, 65) + "\n\n"
+		buf += head("DESCRIPTION") + x.emph(x.Description, 7, width-15) + "\n\n"
 	}
 
 	if x.Source != "" || x.Issues != "" || x.Site != "" {
 
-		buf += util.Emph("**LINKS**", 0, -1) + "\n"
+		buf += head("LINKS")
 
 		if x.Site != "" {
-			buf += "       Site:   " + x.Site + "\n"
+			buf += "       Site:   " + x.paintLink(x.Site) + "\n"
 		}
 
 		if x.Source != "" {
-			buf += "       Source: " + x.Source + "\n"
+			buf += "       Source: " + x.paintLink(x.Source) + "\n"
 		}
 
 		if x.Issues != "" {
-			buf += "       Issues: " + x.Issues + "\n"
+			buf += "       Issues: " + x.paintLink(x.Issues) + "\n"
 		}
 
 		buf += "\n"
@@ -383,15 +615,35 @@ func (x *Command) Help() string {
 	}
 
 	if x.Copyright != "" {
-		buf += util.Emph("**LEGAL**", 0, -1) + "\n" + util.Indent(x.Legal(), 7) + "\n\n"
+		buf += head("LEGAL") + util.Indent(x.Legal(), 7) + "\n\n"
 	}
 
 	return buf
 
 }
 
-// PrintHelp simply prints what Help returns.
-func (x *Command) PrintHelp() { fmt.Print(x.Help()) }
+// emph returns util.EmphIf with emphasis only when color is enabled for
+// the Box of x (see Color, ForceColor, and NO_COLOR).
+func (x *Command) emph(text string, indent, width int) string {
+	return util.EmphIf(x.Box().colorOn(), text, indent, width)
+}
+
+// paint returns Theme.Paint for the Theme of the Box of x with color
+// only when enabled for it (the same as emph).
+func (x *Command) paint(spec, text string) string {
+	return x.Box().theme().paint(x.Box().colorOn(), spec, text)
+}
+
+// paintLink returns Theme.PaintLink for the Theme of the Box of x with
+// color only when enabled for it (the same as emph).
+func (x *Command) paintLink(url string) string {
+	return x.Box().theme().paintLink(x.Box().colorOn(), url)
+}
+
+// PrintHelp prints what Help returns through Page (of the Box, see
+// Box.Page) so that long help documentation is paged on interactive
+// terminals.
+func (x *Command) PrintHelp() { x.Box().Page(x.Help()) }
 
 // AddHelp adds a basic h|help subcommand sets x.Default to it if unset.
 // As of v0.7.7 help is no longer automatically added to allows the
@@ -400,22 +652,22 @@ func (x *Command) PrintHelp() { fmt.Print(x.Help()) }
 func (x *Command) AddHelp() {
 	x.Add("h|help")
 
-	if DEBUG {
+	if x.Box().debug() {
 		util.Log("ADDING HELP: " + x.Name)
 	}
 
 	if x.Default == "" {
 		x.Default = "help"
-		if DEBUG {
+		if x.Box().debug() {
 			util.Log("CHANGING " + x.Name + " DEFAULT TO " + x.Default)
 		}
 	} else {
-		if DEBUG {
+		if x.Box().debug() {
 			util.Log("KEEPING " + x.Name + "DEFAULT TO " + x.Default)
 		}
 	}
 
-	h := Add(x.Name + " help")
+	h := x.Box().Add(x.Name + " help")
 	h.Usage = `[COMMAND]`
 	h.Summary = `display command help information`
 	h.Description = `
@@ -441,19 +693,25 @@ func (x *Command) AddHelp() {
 func (x *Command) ResolveDelegate(args []string) *Command {
 	var xx *Command
 
-	if x.Caller != nil {
-		xx = Get(x.Caller.Name + " " + args[0])
+	x.Lock()
+	caller := x.Caller
+	x.Unlock()
+
+	if caller != nil {
+		xx = x.Box().Get(caller.Name + " " + args[0])
 		if xx != nil {
 			return xx
 		}
 	}
 
-	xx = Get(Main.Name + " " + args[0])
-	if xx != nil {
-		return xx
+	if main := x.Box().main(); main != nil {
+		xx = x.Box().Get(main.Name + " " + args[0])
+		if xx != nil {
+			return xx
+		}
 	}
 
-	return Get(args[0])
+	return x.Box().Get(args[0])
 
 }
 
@@ -467,7 +725,9 @@ func (x *Command) Sigs() *util.StringMap {
 
 // Titles returns a single string with the titles of each subcommand
 // indented and with a maximum title signature length for justification.
-// Hidden commands are not included.
+// Summaries that would not fit within the HelpWidth are truncated with
+// an ellipsis (...). Hidden commands are not included. Lazy Commands are
+// never built (see AddLazy).
 //
 func (x *Command) Titles(indent, max int) string {
 	buf := ""
@@ -480,18 +740,29 @@ func (x *Command) Titles(indent, max int) string {
 	for _, name := range x.Commands.Values() {
 		summary := "<not yet implemented>"
 		c := x.Resolve(name)
-		if util.InSlice(name, x.Hidden) {
+		if util.InSlice(name, x.Hidden) || c.Unavailable() != nil {
 			continue
 		}
 		if c != nil {
-			summary = c.Summary
+			summary = c.badge() + c.Summary
 		}
-		buf += fmt.Sprintf("%-"+fmt.Sprintf("%v", limit)+"v - %v\n",
-			sigs.Get(name), summary)
+		sig := sigs.Get(name)
+		pad := fmt.Sprintf("%-"+fmt.Sprintf("%v", limit)+"v - ", sig)
+		buf += x.paint(x.Box().theme().Name, sig) + pad[len(sig):] +
+			truncate(summary, HelpWidth()-indent-len(pad)) + "\n"
 	}
 	return util.Indent(buf, indent)
 }
 
+// truncate shortens buf to width columns (ending it with an ellipsis)
+// if it is longer. Nothing shorter than the ellipsis itself is truncated.
+func truncate(buf string, width int) string {
+	if width < 4 {
+		return buf
+	}
+	return util.Truncate(buf, width)
+}
+
 // Resolve looks up the Command from the register based on the name
 // passed. First it looks for a fully qualified entry in the register
 // (x.Name + " " + name), then it just looks for the name alone. Returns
@@ -500,33 +771,39 @@ func (x *Command) Titles(indent, max int) string {
 // register their subcommands may not yet have been registered. Resolve
 // allows this lookup to happen reliably later in runtime.
 func (x *Command) Resolve(name string) *Command {
-	n := Reg.Get(x.Name + " " + name)
+	n := x.Box().Get(x.Name + " " + name)
 	if n == nil {
-		n = Reg.Get(name)
+		n = x.Box().Get(name)
 	}
 	return n
 }
 
-// Call is a convenience method that calls cmdbox.Call(x,"foo",args...).
+// Call is a convenience method that calls cmdbox.Call(x,"foo",args...)
+// (or the Box.Call of the Box the Command was added to).
 func (x *Command) Call(name string, args ...string) error {
-	return Call(x, name, args...)
+	return x.Box().Call(x, name, args...)
 }
 
 // ---------------------------- marshaling ----------------------------
 
 // RawJSON calls MustRawJSON on the internal map.
-func (m Command) RawJSON() string { return util.MustRawJSON(m) }
+func (m *Command) RawJSON() string { return util.MustRawJSON(m) }
 
 // JSON calls util.MustJSON on the internal map. It is often more
 // convenient to simply print/Print instead since the String (from
 // fmt.Stringer interface) does the same thing.
 //
-func (m Command) JSON() string { return util.MustJSON(m) }
+func (m *Command) JSON() string { return util.MustJSON(m) }
 
 // String fulfills fmt.Stringer interface as JSON.
 //
-func (m Command) String() string { return util.MustJSON(m) }
+func (m *Command) String() string {
+	if m == nil {
+		return "<nil>"
+	}
+	return util.MustJSON(m)
+}
 
 // Print outputs as JSON (nice when testing).
 //
-func (m Command) Print() { fmt.Print(util.MustJSON(m)) }
+func (m *Command) Print() { fmt.Print(util.MustJSON(m)) }
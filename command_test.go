@@ -18,9 +18,11 @@ package cmdbox_test
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/rwxrob/cmdbox"
 	"github.com/rwxrob/cmdbox/comp"
+	"github.com/rwxrob/cmdbox/term"
 )
 
 func ExampleNewCommand_simple() {
@@ -288,6 +290,41 @@ func ExampleCommand_Titles() {
 
 }
 
+// columns sets the width of the terminal through COLUMNS (see
+// term.UpdateWinSize) and returns a function to put it back.
+func columns(n int) func() {
+	prev, set := os.LookupEnv("COLUMNS")
+	width := term.Width()
+	os.Setenv("COLUMNS", fmt.Sprint(n))
+	term.UpdateWinSize()
+	return func() {
+		os.Setenv("COLUMNS", fmt.Sprint(width))
+		term.UpdateWinSize()
+		if set {
+			os.Setenv("COLUMNS", prev)
+		} else {
+			os.Unsetenv("COLUMNS")
+		}
+	}
+}
+
+func ExampleCommand_Titles_truncated() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	defer columns(30)() // below HelpMinWidth
+
+	x := cmdbox.Add("foo", "bar")
+	b := cmdbox.Add("bar")
+	b.Summary = `does bar stuff that takes a long time to explain`
+
+	fmt.Println(cmdbox.HelpWidth())
+	fmt.Println(x.Titles(2, 0))
+
+	// Output:
+	// 40
+	//   bar - does bar stuff that takes a l...
+}
+
 func ExampleCommand_Resolve() {
 	cmdbox.TestOn()
 	defer cmdbox.TestOff()
@@ -338,3 +375,49 @@ func ExampleCommand_Resolve_subcommand() {
 	// bar
 	// bar
 }
+
+func ExampleCommand_Expand() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.NewCommand("foo", "status", "stop", "secret")
+	x.Hidden = []string{"secret"}
+
+	fmt.Println(x.Expand("sta"))
+	x.Abbrev = true
+	fmt.Println(x.Expand("sta"))
+	fmt.Println(x.Expand("se"))
+	fmt.Println(x.Expand("secret"))
+	fmt.Println(x.Expand("s"))
+
+	// Output:
+	//  <nil>
+	// status <nil>
+	//  <nil>
+	// secret <nil>
+	//  ambiguous command: s (status|stop)
+}
+
+func ExampleCommand_Arg() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("greet")
+	x.Prompt = true // only prompts from interactive terminals
+	x.Method = func(args ...string) error {
+		name, err := x.Arg(args, 0, "name")
+		if err != nil {
+			return err
+		}
+		fmt.Println("hello", name)
+		return nil
+	}
+
+	fmt.Println(x.Call("greet", "rob"))
+	fmt.Println(x.Call("greet"))
+
+	// Output:
+	// hello rob
+	// <nil>
+	// missing argument for name
+}
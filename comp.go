@@ -39,8 +39,12 @@ var DefaultComplete = CompFunc(CompleteCommand)
 
 // CompleteCommand takes a pointer to a Command (x) returning a list of
 // lexigraphically sorted combination of strings from x.Commands that
-// are found in the internal register and x.Params that match the
-// current completion context with any x.Hidden strings removed. Returns
+// are found in the internal register, the first subcommand word of any
+// x.Plugins (unless the word is already a registered name), and
+// x.Params that match the
+// current completion context with any x.Hidden strings and unavailable
+// or deprecated subcommands (see Command.Available and
+// Command.Deprecated) removed. Returns
 // an empty list if anything fails.  Note that no assertion validating
 // that the specified command names exist in the register. See the
 // Command.Complete method and comp subpackage.
@@ -60,8 +64,37 @@ func CompleteCommand(x *Command) []string {
 			rv = append(rv, k)
 		}
 	}
+	var plugins []Plugin
+	if word == " " || x.Commands.Get(word) == "" {
+		plugins = x.Plugins() // only scan the PATH when it might help
+	}
+	for _, p := range plugins {
+		sub := strings.Fields(p.Sub(x))[0]
+		if (word == " " || strings.HasPrefix(sub, word)) &&
+			x.Commands.Get(sub) == "" && !util.InSlice(sub, rv) {
+			rv = append(rv, sub)
+		}
+	}
 	rv = util.OmitFromSlice(rv, x.Hidden)
 	rv = util.OmitFromSlice(rv, x.Commands.Aliases())
+	rv = omitUnlisted(x, rv)
 	sort.Strings(rv)
 	return rv
 }
+
+// omitUnlisted removes the subcommands of x from the words that are
+// deprecated or not currently available. Only those words are checked
+// (and lazy Commands are never built, see AddLazy).
+func omitUnlisted(x *Command, words []string) []string {
+	rv := []string{}
+	for _, word := range words {
+		if name := x.Commands.Get(word); name != "" {
+			c := x.Resolve(name)
+			if (c != nil && c.Deprecated != nil) || c.Unavailable() != nil {
+				continue
+			}
+		}
+		rv = append(rv, word)
+	}
+	return rv
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox
+
+import "fmt"
+
+// Deprecation marks a Command as deprecated (see Command.Deprecated).
+// Message explains why (or anything else users should know),
+// Replacement is the name in the register of the Command to use instead
+// (to which calls are forwarded), and Removal is the version in which
+// the Command is expected to be removed. All are optional.
+//
+type Deprecation struct {
+	Message     string `json:"message,omitempty" yaml:",omitempty"`
+	Replacement string `json:"replacement,omitempty" yaml:",omitempty"`
+	Removal     string `json:"removal,omitempty" yaml:",omitempty"`
+}
+
+// Stability is the level of stability promised for a Command (see
+// Command.Stability). Empty means nothing has been promised either way.
+type Stability string
+
+// Stability levels (see Stability).
+const (
+	Experimental Stability = "experimental"
+	Stable       Stability = "stable"
+)
+
+// Deprecation returns a single sentence describing the deprecation of
+// x (or an empty string if it is not deprecated), which is the warning
+// printed when it is called.
+//
+func (x *Command) Deprecation() string {
+	if x == nil || x.Deprecated == nil {
+		return ""
+	}
+	d := x.Deprecated
+	msg := x.Name + " is deprecated"
+	if d.Removal != "" {
+		msg += " and will be removed in " + d.Removal
+	}
+	if d.Replacement != "" {
+		msg += ", use " + d.Replacement + " instead"
+	}
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	return msg
+}
+
+// warnDeprecated prints the Deprecation of x to the standard error of
+// the Box, but only the first time it is called for x.
+func (b *Box) warnDeprecated(x *Command) {
+	x.warned.Do(func() {
+		fmt.Fprintln(b.stderr(), "warning: "+x.Deprecation())
+	})
+}
+
+// badge returns the marks added to the summary of x wherever it is
+// listed (see Titles and Tree) to note deprecation and stability.
+func (x *Command) badge() string {
+	switch {
+	case x == nil:
+		return ""
+	case x.Deprecated != nil:
+		return "(deprecated) "
+	case x.Stability == Experimental:
+		return "(experimental) "
+	}
+	return ""
+}
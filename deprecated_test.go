@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/comp"
+)
+
+func ExampleCommand_Deprecated() {
+	b := cmdbox.NewBox()
+	var stderr bytes.Buffer
+	b.Stderr = &stderr
+
+	x := b.Add("foo", "ls", "list", "beta")
+	ls := b.Add("foo ls")
+	ls.Summary = "list things"
+	ls.Deprecated = &cmdbox.Deprecation{
+		Replacement: "foo list",
+		Removal:     "v2.0.0",
+	}
+	list := b.Add("foo list")
+	list.Summary = "list things"
+	list.Method = func(args ...string) error {
+		fmt.Println("listing", args)
+		return nil
+	}
+	beta := b.Add("foo beta")
+	beta.Summary = "try new things"
+	beta.Stability = cmdbox.Experimental
+
+	b.Call(nil, "foo", "ls", "all")
+	b.Call(nil, "foo", "ls")
+	fmt.Print(stderr.String())
+	fmt.Println(b.Explain(nil, "foo", "ls").Path)
+
+	fmt.Println(x.Titles(0, 0))
+	comp.This = "foo l"
+	x.Complete()
+	comp.This = ""
+	fmt.Println(ls.JSON())
+
+	// Output:
+	// listing [all]
+	// listing []
+	// warning: foo ls is deprecated and will be removed in v2.0.0, use foo list instead
+	// [foo foo list]
+	// beta - (experimental) try new things
+	// list - list things
+	// ls   - (deprecated) list things
+	// list
+	// {
+	//     "name": "foo ls",
+	//     "summary": "list things",
+	//     "commands": {},
+	//     "origin": {
+	//       "package": "github.com/rwxrob/cmdbox_test",
+	//       "file": "deprecated_test.go",
+	//       "module": "github.com/rwxrob/cmdbox"
+	//     },
+	//     "deprecated": {
+	//       "replacement": "foo list",
+	//       "removal": "v2.0.0"
+	//     }
+	//   }
+}
+
+func ExampleCommand_Deprecation() {
+	x := cmdbox.NewCommand("old")
+	fmt.Printf("%q\n", x.Deprecation())
+	x.Deprecated = &cmdbox.Deprecation{Message: "it never worked"}
+	fmt.Println(x.Deprecation())
+
+	// Output:
+	// ""
+	// old is deprecated: it never worked
+}
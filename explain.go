@@ -0,0 +1,165 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// Step is a single step taken while resolving a Command (see Explain).
+// Action is one of the following:
+//
+//     lookup      - Key looked up in the register, Match is it if found
+//     expand      - Key (first argument) expanded to Match subcommand
+//                   name (see Command.Expand), or the error if ambiguous
+//     default     - Key Command falling back to its Default (Match)
+//     plugin      - Key plugin executable found at Match (see Plugin)
+//     unavailable - Key Command not available, Match is why (see
+//                   Command.Available)
+//     deprecated  - Key Command deprecated, forwarded to the Match
+//                   replacement if any (see Command.Deprecated)
+//     method      - Key Command Method chosen
+//     handler     - Key Command Handler chosen
+//     result      - Key Command Result chosen
+//
+// Args are the arguments remaining at that step.
+//
+type Step struct {
+	Action string   `json:"action"`
+	Key    string   `json:"key"`
+	Match  string   `json:"match,omitempty"`
+	Args   []string `json:"args"`
+}
+
+// Trace is the full explanation of how a Command name and arguments are
+// resolved (see Explain). Path is the names of the Commands traversed
+// (see Invocation.Path), Chosen is the Action of the last Step that
+// chose what to call (empty if nothing was found), and Args are the
+// arguments it would be passed. Error is that returned by resolution
+// (if any).
+//
+type Trace struct {
+	Name   string   `json:"name"`
+	Argv   []string `json:"argv"`
+	Steps  []Step   `json:"steps"`
+	Path   []string `json:"path,omitempty"`
+	Chosen string   `json:"chosen,omitempty"`
+	Args   []string `json:"args"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// add appends a Step (and is safe to call on a nil Trace, which does
+// nothing).
+func (t *Trace) add(action, key, match string, args []string) {
+	if t == nil {
+		return
+	}
+	a := make([]string, len(args))
+	copy(a, args)
+	t.Steps = append(t.Steps, Step{action, key, match, a})
+}
+
+// found adds a Step with the key as its Match if x is not nil.
+func (t *Trace) found(action, key string, x *Command, args []string) {
+	var match string
+	if x != nil {
+		match = x.Name
+	}
+	t.add(action, key, match, args)
+}
+
+// Explain resolves the name and arguments exactly as Call would
+// (without calling anything) and returns every step taken. It is
+// useful when something resolves to the wrong place. Commands added
+// with AddLazy are built if they are looked up. See Trace and AddWhich.
+//
+func Explain(caller *Command, name string, args ...string) *Trace {
+	return Default.Explain(caller, name, args...)
+}
+
+// Explain is the Box equivalent of the package Explain function.
+func (b *Box) Explain(caller *Command, name string, args ...string) *Trace {
+	t := &Trace{Name: name, Argv: args, Steps: []Step{}}
+	inv := &Invocation{Box: b, Caller: caller, Name: name, Argv: args,
+		trace: t}
+	method, margs, path, err := b.resolve(inv, caller, name, args)
+	if err != nil {
+		t.Error = err.Error()
+	}
+	if method != nil {
+		for _, c := range path {
+			t.Path = append(t.Path, c.Name)
+		}
+		t.Chosen = t.Steps[len(t.Steps)-1].Action
+		t.Args = margs
+	}
+	if t.Args == nil {
+		t.Args = []string{}
+	}
+	return t
+}
+
+// String fulfills the fmt.Stringer interface with a table of the Steps
+// followed by a summary of what would be called (suitable for humans,
+// see Render).
+//
+func (t *Trace) String() string {
+	tb := util.NewTable("STEP", "KEY", "MATCH", "ARGS")
+	for _, s := range t.Steps {
+		match := s.Match
+		if match == "" {
+			match = "-"
+		}
+		tb.Add(s.Action, s.Key, match, fmt.Sprintf("%q", s.Args))
+	}
+	buf := tb.String()
+	switch {
+	case t.Error != "":
+		buf += "error: " + t.Error + "\n"
+	case t.Chosen == "":
+		buf += "unresolved: " + strings.TrimSpace(t.Name+" "+
+			strings.Join(t.Argv, " ")) + "\n"
+	default:
+		buf += fmt.Sprintf("calls %v of %v with %q\n", t.Chosen,
+			t.Path[len(t.Path)-1], t.Args)
+	}
+	return buf
+}
+
+// AddWhich adds a which subcommand that explains how the words that
+// follow it would be resolved if passed to x (see Explain). The trace
+// is printed as text on a terminal and as JSON otherwise (or in any of
+// the Formats named by a trailing word, see OutputFormat).
+//
+func (x *Command) AddWhich() {
+	x.Add("which")
+	w := x.Box().Add(x.Name + " which")
+	w.Usage = `COMMAND...`
+	w.Summary = `explain which command would be called`
+	w.Description = `
+		Prints every step taken to resolve the commands and arguments that
+		follow (which keys were looked up, which matched, where defaults
+		were used, and what would finally be called with which arguments)
+		without calling anything.`
+	w.Result = func(args ...string) (interface{}, error) {
+		return x.Box().Explain(x, x.Name, args...), nil
+	}
+	x.UpdateUsage()
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleExplain() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "bar", "other")
+	x.Default = "other"
+	cmdbox.Add("foo bar", "baz")
+	baz := cmdbox.Add("foo bar baz")
+	baz.Method = func(args ...string) error { return nil }
+	other := cmdbox.Add("foo other")
+	other.Method = func(args ...string) error { return nil }
+
+	fmt.Print(cmdbox.Explain(x, "foo", "bar", "baz", "some"))
+	fmt.Print(cmdbox.Explain(x, "foo", "nope"))
+	fmt.Print(cmdbox.Explain(nil, "missing"))
+
+	// Output:
+	// STEP    KEY              MATCH        ARGS
+	// lookup  foo foo          -            ["bar" "baz" "some"]
+	// lookup  foo              foo          ["bar" "baz" "some"]
+	// expand  bar              bar          ["bar" "baz" "some"]
+	// lookup  foo foo bar      -            ["baz" "some"]
+	// lookup  foo bar          foo bar      ["baz" "some"]
+	// expand  baz              baz          ["baz" "some"]
+	// lookup  foo foo bar baz  -            ["some"]
+	// lookup  foo bar baz      foo bar baz  ["some"]
+	// method  foo bar baz      foo bar baz  ["some"]
+	// calls method of foo bar baz with ["some"]
+	// STEP     KEY            MATCH      ARGS
+	// lookup   foo foo        -          ["nope"]
+	// lookup   foo            foo        ["nope"]
+	// expand   nope           -          ["nope"]
+	// default  foo            other      ["nope"]
+	// lookup   foo foo other  -          ["nope"]
+	// lookup   foo other      foo other  ["nope"]
+	// method   foo other      foo other  ["nope"]
+	// calls method of foo other with ["nope"]
+	// STEP    KEY      MATCH  ARGS
+	// lookup  missing  -      []
+	// unresolved: missing
+}
+
+func ExampleCommand_AddWhich() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "bar")
+	x.AddWhich()
+	bar := cmdbox.Add("foo bar")
+	bar.Method = func(args ...string) error { return nil }
+
+	cmdbox.Call(x, "foo", "which", "bar", "some", "json")
+
+	// Output:
+	// {
+	//     "name": "foo",
+	//     "argv": [
+	//       "bar",
+	//       "some"
+	//     ],
+	//     "steps": [
+	//       {
+	//         "action": "lookup",
+	//         "key": "foo foo",
+	//         "args": [
+	//           "bar",
+	//           "some"
+	//         ]
+	//       },
+	//       {
+	//         "action": "lookup",
+	//         "key": "foo",
+	//         "match": "foo",
+	//         "args": [
+	//           "bar",
+	//           "some"
+	//         ]
+	//       },
+	//       {
+	//         "action": "expand",
+	//         "key": "bar",
+	//         "match": "bar",
+	//         "args": [
+	//           "bar",
+	//           "some"
+	//         ]
+	//       },
+	//       {
+	//         "action": "lookup",
+	//         "key": "foo foo bar",
+	//         "args": [
+	//           "some"
+	//         ]
+	//       },
+	//       {
+	//         "action": "lookup",
+	//         "key": "foo bar",
+	//         "match": "foo bar",
+	//         "args": [
+	//           "some"
+	//         ]
+	//       },
+	//       {
+	//         "action": "method",
+	//         "key": "foo bar",
+	//         "match": "foo bar",
+	//         "args": [
+	//           "some"
+	//         ]
+	//       }
+	//     ],
+	//     "path": [
+	//       "foo",
+	//       "foo bar"
+	//     ],
+	//     "chosen": "method",
+	//     "args": [
+	//       "some"
+	//     ]
+	//   }
+}
+
+func ExampleExplain_ambiguousDefault() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "start", "stop", "run")
+	x.Abbrev = true
+	x.Default = "run"
+	run := cmdbox.Add("foo run")
+	run.Method = func(args ...string) error { return nil }
+
+	fmt.Print(cmdbox.Explain(nil, "foo", "st"))
+
+	// Output:
+	// STEP     KEY      MATCH                               ARGS
+	// lookup   foo      foo                                 ["st"]
+	// expand   st       ambiguous command: st (start|stop)  ["st"]
+	// default  foo      run                                 ["st"]
+	// lookup   foo run  foo run                             ["st"]
+	// method   foo run  foo run                             ["st"]
+	// calls method of foo run with ["st"]
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Formats are the output formats supported for the results of
+// a Command.Result (see Render). The "text" format is for humans and
+// the others are for programs.
+//
+var Formats = []string{"text", "json", "yaml", "tsv"}
+
+// Format forces the output format for every Command.Result no matter
+// what its Command.Format is or whether output is to a terminal. It is
+// set at init() time from the CMDBOX_FORMAT environment variable (if
+// any). See Render.
+//
+var Format string
+
+func init() {
+	Format = os.Getenv("CMDBOX_FORMAT")
+}
+
+// Result represents a function to be used as Command.Result values.
+// Rather than print anything itself a Result returns structured data
+// to be rendered by cmdbox in the format requested (see Render).
+//
+type Result func(args ...string) (interface{}, error)
+
+// isFormat returns true if name is one of the Formats.
+func isFormat(name string) bool {
+	for _, f := range Formats {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// OutputFormat returns the format to use for the Result of the Command
+// given the arguments that will be passed to it along with the arguments
+// that remain after removing a reserved trailing format word (if any).
+// The first of the following is used:
+//
+// * A trailing argument matching one of the Formats (foo list json)
+// * The package Format (from CMDBOX_FORMAT)
+// * The Command.Format
+// * "text" if output is to a terminal, "json" if not
+//
+// Note that the trailing format word is only reserved for Commands with
+// a Result and never for those with a plain Method.
+//
+func (x *Command) OutputFormat(args []string) (string, []string) {
+	if n := len(args); n > 0 && isFormat(args[n-1]) {
+		return args[n-1], args[:n-1]
+	}
+	switch {
+	case x.Box().format() != "":
+		return x.Box().format(), args
+	case x.Format != "":
+		return x.Format, args
+	case x.Box().isTerminal():
+		return "text", args
+	}
+	return "json", args
+}
+
+// resultMethod wraps the Result into a Method that renders its value in
+// the negotiated OutputFormat.
+func (x *Command) resultMethod() Method {
+	return func(args ...string) error {
+		format, args := x.OutputFormat(args)
+		if !isFormat(format) {
+			return UnknownFormat(format)
+		}
+		v, err := x.Result(args...)
+		if err != nil {
+			return err
+		}
+		out, err := Render(v, format)
+		if err != nil {
+			return err
+		}
+		b := x.Box()
+		if format == "text" {
+			return b.Page(out)
+		}
+		fmt.Fprint(b.stdout(), out)
+		return nil
+	}
+}
+
+// Render returns the value rendered in the given format (see Formats):
+//
+// text - for humans: strings, fmt.Stringers (including *util.Table),
+// and errors as they are, slices one item per line, and maps as a two
+// column table of keys and values. Anything else is rendered as YAML
+// (which is reasonably readable).
+//
+// json - pretty JSON (see util.JSON)
+//
+// yaml - YAML
+//
+// tsv - tab-separated values for awk, cut, and friends: scalars on
+// a single line, slices one item per line, maps as key and value lines,
+// and slices of objects (structs or maps) as rows with a header line of
+// their sorted keys. A *util.Table is rendered with its own TSV method.
+//
+// Values are first converted as they would be with JSON so that json
+// struct tags are observed for every format. Output always ends with
+// a line return (unless empty).
+//
+func Render(v interface{}, format string) (string, error) {
+	if t, is := v.(*util.Table); is {
+		switch format {
+		case "text":
+			return t.String(), nil
+		case "tsv":
+			return t.TSV(), nil
+		}
+		v = t.Data()
+	}
+	var out string
+	switch format {
+	case "text":
+		out = renderText(v)
+	case "json":
+		var err error
+		out, err = util.JSON(v)
+		if err != nil {
+			return "", err
+		}
+	case "yaml":
+		d, err := generic(v)
+		if err != nil {
+			return "", err
+		}
+		byt, err := yaml.Marshal(d)
+		if err != nil {
+			return "", err
+		}
+		out = string(byt)
+	case "tsv":
+		d, err := generic(v)
+		if err != nil {
+			return "", err
+		}
+		out = renderTSV(d)
+	default:
+		return "", UnknownFormat(format)
+	}
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// generic converts v into the generic types (maps, slices, strings,
+// numbers, and bools) of a JSON round trip. Whole numbers become int64
+// (rather than float64) so that large ones are never rendered in
+// scientific notation.
+func generic(v interface{}) (interface{}, error) {
+	byt, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(byt))
+	dec.UseNumber()
+	var d interface{}
+	if err := dec.Decode(&d); err != nil {
+		return nil, err
+	}
+	return numbers(d), nil
+}
+
+// numbers replaces every json.Number within d with an int64 (if whole)
+// or float64.
+func numbers(d interface{}) interface{} {
+	switch s := d.(type) {
+	case json.Number:
+		if i, err := s.Int64(); err == nil {
+			return i
+		}
+		f, _ := s.Float64()
+		return f
+	case []interface{}:
+		for i, item := range s {
+			s[i] = numbers(item)
+		}
+	case map[string]interface{}:
+		for k, item := range s {
+			s[k] = numbers(item)
+		}
+	}
+	return d
+}
+
+// scalar returns the string form of a generic value (see generic) with
+// nested objects and arrays rendered as compact JSON.
+func scalar(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case int64, bool:
+		return fmt.Sprint(s)
+	}
+	return util.MustRawJSON(v)
+}
+
+func renderText(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case fmt.Stringer:
+		return s.String()
+	case error:
+		return s.Error()
+	}
+	d, err := generic(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	switch s := d.(type) {
+	case []interface{}:
+		var objects bool
+		lines := make([]string, len(s))
+		for i, item := range s {
+			if _, is := item.(map[string]interface{}); is {
+				objects = true
+				break
+			}
+			lines[i] = scalar(item)
+		}
+		if !objects {
+			return strings.Join(lines, "\n")
+		}
+	case map[string]interface{}:
+		t := util.NewTable()
+		for _, k := range sortedKeys(s) {
+			t.Add(k, scalar(s[k]))
+		}
+		return t.String()
+	case string, int64, float64, bool:
+		return scalar(s)
+	}
+	byt, err := yaml.Marshal(d)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(byt)
+}
+
+func renderTSV(d interface{}) string {
+	t := util.NewTable()
+	switch s := d.(type) {
+	case []interface{}:
+		keys := map[string]bool{}
+		for _, item := range s {
+			if m, is := item.(map[string]interface{}); is {
+				for k := range m {
+					keys[k] = true
+				}
+			}
+		}
+		if len(keys) == 0 {
+			for _, item := range s {
+				t.Add(scalar(item))
+			}
+			break
+		}
+		for k := range keys {
+			t.Header = append(t.Header, k)
+		}
+		sort.Strings(t.Header)
+		for _, item := range s {
+			m, _ := item.(map[string]interface{})
+			row := make([]string, len(t.Header))
+			for i, k := range t.Header {
+				row[i] = scalar(m[k])
+			}
+			t.Add(row...)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(s) {
+			t.Add(k, scalar(s[k]))
+		}
+	default:
+		t.Add(scalar(s))
+	}
+	return t.TSV()
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleRender() {
+	type issue struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	issues := []issue{{1, "fix the thing"}, {2, "add more"}}
+
+	for _, f := range []string{"json", "yaml", "tsv"} {
+		out, _ := cmdbox.Render(issues, f)
+		fmt.Print(out)
+	}
+
+	out, _ := cmdbox.Render(map[string]int{"open": 3, "closed": 12}, "text")
+	fmt.Print(out)
+
+	_, err := cmdbox.Render(issues, "xml")
+	fmt.Println(err)
+
+	// Output:
+	// [
+	//     {
+	//       "id": 1,
+	//       "title": "fix the thing"
+	//     },
+	//     {
+	//       "id": 2,
+	//       "title": "add more"
+	//     }
+	//   ]
+	// - id: 1
+	//   title: fix the thing
+	// - id: 2
+	//   title: add more
+	// id	title
+	// 1	fix the thing
+	// 2	add more
+	// closed  12
+	// open    3
+	// unknown output format: xml
+}
+
+func ExampleRender_largeNumbers() {
+	type file struct {
+		Name string `json:"name"`
+		Size int    `json:"size"`
+	}
+	files := []file{{"big.iso", 1234567}}
+
+	for _, f := range cmdbox.Formats {
+		out, _ := cmdbox.Render(files, f)
+		fmt.Print(out)
+	}
+
+	for _, f := range cmdbox.Formats {
+		out, _ := cmdbox.Render(map[string]int{"count": 2000000}, f)
+		fmt.Print(out)
+	}
+
+	// Output:
+	// - name: big.iso
+	//   size: 1234567
+	// [
+	//     {
+	//       "name": "big.iso",
+	//       "size": 1234567
+	//     }
+	//   ]
+	// - name: big.iso
+	//   size: 1234567
+	// name	size
+	// big.iso	1234567
+	// count  2000000
+	// {
+	//     "count": 2000000
+	//   }
+	// count: 2000000
+	// count	2000000
+}
+
+func ExampleResult() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("issues")
+	x.Result = func(args ...string) (interface{}, error) {
+		t := util.NewTable("id", "title")
+		t.Add("1", "fix the thing")
+		t.Add("2", "add more")
+		return t, nil
+	}
+
+	cmdbox.Call(nil, "issues") // not a terminal, so json
+	cmdbox.Call(nil, "issues", "tsv")
+	cmdbox.Call(nil, "issues", "text")
+
+	// Output:
+	// [
+	//     {
+	//       "id": "1",
+	//       "title": "fix the thing"
+	//     },
+	//     {
+	//       "id": "2",
+	//       "title": "add more"
+	//     }
+	//   ]
+	// id	title
+	// 1	fix the thing
+	// 2	add more
+	// id  title
+	// 1   fix the thing
+	// 2   add more
+}
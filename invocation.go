@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"sync"
+)
+
+// Invocation holds everything about a single call of a Command (see
+// Call) so that nothing about the call needs to be stored on the shared
+// Command itself (which could be called concurrently). Handlers,
+// Middleware, and Hooks are all passed the Invocation.
+//
+// The Box is the one the call was made with (see Box.Call). The Caller
+// is the Command passed to Call (or nil) and Parent is the
+// Invocation that made the call when called with Invocation.Call (or
+// nil). Name and Argv are exactly what was passed to Call. Path is every
+// Command traversed during resolution (see Resolve) ending with the
+// Command that was called, and Args are the arguments passed to it
+// after any shifting.
+//
+type Invocation struct {
+	Box     *Box
+	Parent  *Invocation
+	Caller  *Command
+	Name    string
+	Argv    []string
+	Path    []*Command
+	Command *Command
+	Args    []string
+
+	mu     sync.Mutex
+	values map[string]interface{}
+	trace  *Trace
+	stack  []visit
+}
+
+// visit is a Command being resolved and the number of arguments it had.
+type visit struct {
+	x    *Command
+	args int
+}
+
+// enter pushes the Command onto the resolution stack returning a Cycle
+// error if it is already there with the same number of arguments (no
+// progress) or TooDeep if the stack would exceed max.
+func (inv *Invocation) enter(x *Command, args, max int) error {
+	names := func(from int) []string {
+		n := []string{}
+		for _, v := range inv.stack[from:] {
+			n = append(n, v.x.Name)
+		}
+		return append(n, x.Name)
+	}
+	for i, v := range inv.stack {
+		if v.x == x && v.args == args {
+			return Cycle(names(i))
+		}
+	}
+	if len(inv.stack) >= max {
+		return TooDeep(max, names(0))
+	}
+	inv.stack = append(inv.stack, visit{x, args})
+	return nil
+}
+
+// leave pops the last Command pushed with enter.
+func (inv *Invocation) leave() { inv.stack = inv.stack[:len(inv.stack)-1] }
+
+// Handler represents a function to be used as Command.Handler values.
+// Unlike a Method, a Handler is passed the Invocation (with its
+// arguments as Args).
+//
+type Handler func(inv *Invocation) error
+
+// setPath sets the resolved Path, Command, and Args.
+func (inv *Invocation) setPath(path []*Command, args []string) {
+	inv.Path = path
+	if len(path) > 0 {
+		inv.Command = path[len(path)-1]
+	}
+	inv.Args = args
+}
+
+// Callers returns the chain of calling Commands starting with the
+// nearest (the Caller of this Invocation, then that of its Parent, and
+// so on). Nil callers are omitted.
+//
+func (inv *Invocation) Callers() []*Command {
+	callers := []*Command{}
+	for i := inv; i != nil; i = i.Parent {
+		if i.Caller != nil {
+			callers = append(callers, i.Caller)
+		}
+	}
+	return callers
+}
+
+// Set sets a value for the key that is visible only to this Invocation
+// (and those it calls, see Get). It is safe for concurrency.
+//
+func (inv *Invocation) Set(key string, val interface{}) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if inv.values == nil {
+		inv.values = map[string]interface{}{}
+	}
+	inv.values[key] = val
+}
+
+// Get returns the value for the key set on this Invocation or the
+// nearest Parent that has it (or nil if none). It is safe for
+// concurrency.
+//
+func (inv *Invocation) Get(key string) interface{} {
+	for i := inv; i != nil; i = i.Parent {
+		i.mu.Lock()
+		val, has := i.values[key]
+		i.mu.Unlock()
+		if has {
+			return val
+		}
+	}
+	return nil
+}
+
+// Call calls the named Command just like Call with the Command of this
+// Invocation as the caller and this Invocation as the Parent of the
+// new one.
+//
+func (inv *Invocation) Call(name string, args ...string) error {
+	return inv.Box.invoke(&Invocation{
+		Parent: inv,
+		Caller: inv.Command,
+		Name:   name,
+		Argv:   args,
+	})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleInvocation() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "bar")
+	x.Handler = func(inv *cmdbox.Invocation) error {
+		inv.Set("user", "rob")
+		return inv.Call("bar", inv.Args...)
+	}
+
+	b := cmdbox.Add("foo bar")
+	b.Handler = func(inv *cmdbox.Invocation) error {
+		fmt.Println("name:", inv.Name, inv.Argv)
+		fmt.Println("caller:", inv.Caller.Name)
+		fmt.Println("user:", inv.Get("user"))
+		for _, c := range inv.Path {
+			fmt.Println("path:", c.Name)
+		}
+		fmt.Println("args:", inv.Args)
+		return nil
+	}
+
+	cmdbox.Call(nil, "foo", "some", "thing")
+
+	// Output:
+	// name: bar [some thing]
+	// caller: foo
+	// user: rob
+	// path: foo
+	// path: foo bar
+	// args: [some thing]
+}
+
+func ExampleInvocation_concurrent() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	cmdbox.Add("a", "shared")
+	cmdbox.Add("b", "shared")
+	s := cmdbox.Add("shared")
+
+	var mu sync.Mutex
+	counts := map[string]int{}
+	s.Handler = func(inv *cmdbox.Invocation) error {
+		mu.Lock()
+		defer mu.Unlock()
+		counts[inv.Caller.Name+" "+inv.Args[0]]++
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); cmdbox.Call(cmdbox.Get("a"), "shared", "a") }()
+		go func() { defer wg.Done(); cmdbox.Call(cmdbox.Get("b"), "shared", "b") }()
+	}
+	wg.Wait()
+	fmt.Println(counts)
+
+	// Output:
+	// map[a a:100 b b:100]
+}
+
+func ExampleCommand_ResolveDelegate_concurrent() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	cmdbox.Add("a", "shared")
+	cmdbox.Add("b", "shared")
+	cmdbox.Add("a target")
+	cmdbox.Add("b target")
+	s := cmdbox.Add("shared")
+
+	var mu sync.Mutex
+	found := 0
+	s.Method = func(args ...string) error {
+		if s.ResolveDelegate([]string{"target"}) != nil {
+			mu.Lock()
+			found++
+			mu.Unlock()
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); cmdbox.Call(cmdbox.Get("a"), "shared") }()
+		go func() { defer wg.Done(); cmdbox.Call(cmdbox.Get("b"), "shared") }()
+	}
+	wg.Wait()
+	fmt.Println(found)
+
+	// Output:
+	// 100
+}
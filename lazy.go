@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import "sync"
+
+// lazy holds the constructor of a Command added with AddLazy until it
+// is needed.
+type lazy struct {
+	once  sync.Once
+	build func(x *Command)
+}
+
+// AddLazy adds a Command with only its name and summary, deferring the
+// rest of its construction (documentation, subcommands, Method, and so
+// on) to the build function, which is called once with the Command
+// the first time it is resolved (see Resolve and Call), completed, or
+// has its Help rendered. Listing it in the help of another Command only
+// needs the summary and does not build it. This keeps the startup of
+// composites with many modules fast no matter how many they import.
+//
+//    func init() {
+//      cmdbox.AddLazy("greet", "print a friendly greeting", build)
+//    }
+//
+//    func build(x *cmdbox.Command) {
+//      x.Usage = `[NAME]`
+//      x.Description = `...`
+//      x.Method = func(args ...string) error { ... }
+//    }
+//
+// The build function may also Add subcommands. The returned Command is
+// the same one later passed to build (so changes made to it before then
+// remain unless build changes them).
+//
+// Since help listings and completion never build the Command, anything
+// they need besides the summary (Available, Deprecated, and Stability)
+// must be set on the returned Command rather than by build. Otherwise,
+// it is ignored there until the Command is built for some other reason.
+//
+//    x := cmdbox.AddLazy("kube", "manage the cluster", build)
+//    x.Available = cmdbox.NeedsExec("kubectl")
+//
+func AddLazy(name, summary string, build func(x *Command)) *Command {
+	return Default.AddLazy(name, summary, build)
+}
+
+// AddLazy is the Box equivalent of the package AddLazy function.
+func (b *Box) AddLazy(name, summary string, build func(x *Command)) *Command {
+	x := b.Add(name)
+	x.Summary = summary
+	x.lazy = &lazy{build: build}
+	return x
+}
+
+// Lazy returns true if the Command was added with AddLazy and has not
+// yet been built.
+//
+func (x *Command) Lazy() bool {
+	if x.lazy == nil {
+		return false
+	}
+	x.Lock()
+	defer x.Unlock()
+	return x.lazy.build != nil
+}
+
+// load builds a Command added with AddLazy (only once, even when called
+// concurrently).
+func (x *Command) load() {
+	if x == nil || x.lazy == nil {
+		return
+	}
+	x.lazy.once.Do(func() {
+		x.Lock()
+		build := x.lazy.build
+		x.Unlock()
+		build(x)
+		x.Lock()
+		x.lazy.build = nil
+		x.Unlock()
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleAddLazy() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("tool", "greet")
+	x.AddHelp()
+
+	g := cmdbox.AddLazy("tool greet", "print a friendly greeting",
+		func(x *cmdbox.Command) {
+			fmt.Println("building", x.Name)
+			x.Usage = `[NAME]`
+			x.Method = func(args ...string) error {
+				fmt.Println("hello", args)
+				return nil
+			}
+		})
+
+	// listing it in help only needs the summary
+	fmt.Println(strings.Contains(x.Help(), "print a friendly greeting"))
+	fmt.Println(g.Lazy())
+
+	cmdbox.Call(nil, "tool", "greet", "you")
+	cmdbox.Call(nil, "tool", "greet", "again")
+	fmt.Println(g.Lazy(), g.Usage)
+
+	// Output:
+	// true
+	// true
+	// building tool greet
+	// hello [you]
+	// hello [again]
+	// false [NAME]
+}
+
+func ExampleAddLazy_available() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("tool", "greet", "other")
+	x.AddHelp()
+
+	g := cmdbox.AddLazy("tool greet", "print a friendly greeting",
+		func(x *cmdbox.Command) { fmt.Println("building", x.Name) })
+	g.Available = cmdbox.NeedsEnv("CMDBOX_NEVER_SET")
+
+	// set before build, so honored without building
+	fmt.Println(strings.Contains(x.Help(), "greeting"))
+	fmt.Println(g.Lazy())
+
+	// Output:
+	// false
+	// true
+}
+
+// modules simulates the registration of n imported modules each with
+// a handful of subcommands and documentation.
+func modules(b *cmdbox.Box, n int, lazy bool) {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = "mod" + strings.Repeat("x", i+1)
+	}
+	b.Add("big", names...)
+	for _, name := range names {
+		build := func(x *cmdbox.Command) {
+			x.Add("one", "two", "three", "four", "five")
+			x.Usage = `[one|two|three|four|five]`
+			x.Description = strings.Repeat("Some long description. ", 50)
+			for _, sub := range []string{"one", "two", "three", "four", "five"} {
+				s := b.Add(x.Name + " " + sub)
+				s.Summary = "a subcommand"
+				s.Description = strings.Repeat("Some long description. ", 20)
+				s.Method = func(args ...string) error { return nil }
+			}
+		}
+		if lazy {
+			b.AddLazy("big "+name, "a module", build)
+			continue
+		}
+		x := b.Add("big " + name)
+		x.Summary = "a module"
+		build(x)
+	}
+}
+
+func benchmarkStartup(bm *testing.B, lazy bool) {
+	for i := 0; i < bm.N; i++ {
+		b := cmdbox.NewBox()
+		modules(b, 200, lazy)
+		b.Call(nil, "big", "modxxx", "two")
+	}
+}
+
+func BenchmarkStartup(b *testing.B)      { benchmarkStartup(b, false) }
+func BenchmarkStartup_lazy(b *testing.B) { benchmarkStartup(b, true) }
@@ -0,0 +1,316 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/valid"
+)
+
+// LinkNames returns the sorted names of every Command in the register
+// that can be called directly when the executable is linked to it (see
+// ExecutedAs). Only single word names are eligible (not subcommands
+// or unresolved duplicates).
+//
+func LinkNames() []string { return Default.LinkNames() }
+
+// LinkNames is the Box equivalent of the package LinkNames function.
+func (b *Box) LinkNames() []string {
+	names := []string{}
+	for _, name := range b.Names() {
+		if valid.Name(name) && !strings.Contains(name, " ") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// executable returns the absolute path of the running executable with
+// any symbolic links resolved.
+func executable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// isLinkTo returns true if path is a symbolic link to exe (even if
+// dangling) or a hard link to the same file.
+func isLinkTo(path, exe string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, _ := os.Readlink(path); target == exe {
+			return true
+		}
+		info, err = os.Stat(path)
+		if err != nil {
+			return false
+		}
+	}
+	einfo, err := os.Stat(exe)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(info, einfo)
+}
+
+// isMainName returns true if name is that by which the executable was
+// called or that of the main command of the Box (see Main).
+func (b *Box) isMainName(name string) bool {
+	if len(os.Args) > 0 && name == filepath.Base(os.Args[0]) {
+		return true
+	}
+	m := b.main()
+	return m != nil && name == m.Name
+}
+
+// Link creates a symbolic (or hard) link in dir to the running
+// executable for each of the LinkNames (except the name of the
+// executable and main command itself, see Main) and returns the names
+// of those created. Links that already exist are left alone and files
+// with the same name that are not links to the executable are never
+// replaced (returning an error naming them after all others have been
+// linked).
+//
+func Link(dir string, hard bool) ([]string, error) {
+	return Default.Link(dir, hard)
+}
+
+// Link is the Box equivalent of the package Link function.
+func (b *Box) Link(dir string, hard bool) ([]string, error) {
+	exe, err := executable()
+	if err != nil {
+		return nil, err
+	}
+	linked := []string{}
+	conflicts := []string{}
+	for _, name := range b.LinkNames() {
+		path := filepath.Join(dir, name)
+		if path == exe || b.isMainName(name) || isLinkTo(path, exe) {
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil {
+			conflicts = append(conflicts, path)
+			continue
+		}
+		if hard {
+			err = os.Link(exe, path)
+		} else {
+			err = os.Symlink(exe, path)
+		}
+		if err != nil {
+			return linked, err
+		}
+		linked = append(linked, name)
+	}
+	if len(conflicts) > 0 {
+		return linked, LinkConflict(conflicts)
+	}
+	return linked, nil
+}
+
+// links returns the sorted names of the entries in dir that are links
+// to the running executable (see isLinkTo) other than the main command
+// itself (see isMainName), which is often a link to the executable
+// installed by a package manager.
+func (b *Box) links(dir string) ([]string, string, error) {
+	exe, err := executable()
+	if err != nil {
+		return nil, "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, exe, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if path != exe && !b.isMainName(e.Name()) && isLinkTo(path, exe) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, exe, nil
+}
+
+// Unlink removes every link in dir to the running executable (see Link)
+// and returns the names of those removed. This includes links that are
+// not (or no longer) among the LinkNames, such as those for renamed
+// commands (see StaleLinks) or made by hand. Nothing else is touched
+// (including a link named after the main command itself).
+//
+func Unlink(dir string) ([]string, error) { return Default.Unlink(dir) }
+
+// Unlink is the Box equivalent of the package Unlink function.
+func (b *Box) Unlink(dir string) ([]string, error) {
+	names, _, err := b.links(dir)
+	if err != nil {
+		return nil, err
+	}
+	removed := []string{}
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// StaleLinks returns the names of links in dir to the running
+// executable that no longer match any of the LinkNames (usually because
+// a command was renamed or removed since Link was last run). A link
+// named after the main command itself is never stale.
+//
+func StaleLinks(dir string) ([]string, error) {
+	return Default.StaleLinks(dir)
+}
+
+// StaleLinks is the Box equivalent of the package StaleLinks function.
+func (b *Box) StaleLinks(dir string) ([]string, error) {
+	names, _, err := b.links(dir)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]bool{}
+	for _, name := range b.LinkNames() {
+		current[name] = true
+	}
+	stale := []string{}
+	for _, name := range names {
+		if !current[name] {
+			stale = append(stale, name)
+		}
+	}
+	return stale, nil
+}
+
+// CompleteLines returns the bash "complete -C" lines enabling tab
+// completion for each of the LinkNames as linked into dir, suitable for
+// adding to a bashrc file.
+//
+func CompleteLines(dir string) string { return Default.CompleteLines(dir) }
+
+// CompleteLines is the Box equivalent of the package CompleteLines
+// function.
+func (b *Box) CompleteLines(dir string) string {
+	var buf string
+	for _, name := range b.LinkNames() {
+		path := filepath.Join(dir, name)
+		buf += fmt.Sprintf("complete -C %v %v\n", path, name)
+	}
+	return buf
+}
+
+// AddLinks adds a links subcommand for installing the composite as
+// a BusyBox-style toolbox of individual commands (see Link, Unlink,
+// StaleLinks, and CompleteLines). The directory defaults to that of the
+// executable itself.
+//
+func (x *Command) AddLinks() {
+	x.Add("links")
+	b := x.Box()
+	l := b.Add(x.Name+" links", "install", "remove", "stale", "complete")
+	l.Summary = `manage links to each command`
+	l.Description = `
+		Manages symbolic (or hard) links to this executable, one for each
+		of its commands, so that each can be called directly by name. The
+		directory defaults to the one containing the executable.`
+
+	dir := func(args []string) (string, error) {
+		if len(args) > 0 {
+			return args[0], nil
+		}
+		exe, err := executable()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Dir(exe), nil
+	}
+
+	i := b.Add(x.Name + " links install")
+	i.Usage = `[hard] [DIR]`
+	i.Summary = `link each command into a directory`
+	i.Method = func(args ...string) error {
+		hard := len(args) > 0 && args[0] == "hard"
+		if hard {
+			args = args[1:]
+		}
+		d, err := dir(args)
+		if err != nil {
+			return err
+		}
+		linked, err := b.Link(d, hard)
+		for _, name := range linked {
+			fmt.Fprintln(b.stdout(), filepath.Join(d, name))
+		}
+		return err
+	}
+
+	r := b.Add(x.Name + " links remove")
+	r.Usage = `[DIR]`
+	r.Summary = `remove all links from a directory`
+	r.Method = func(args ...string) error {
+		d, err := dir(args)
+		if err != nil {
+			return err
+		}
+		removed, err := b.Unlink(d)
+		for _, name := range removed {
+			fmt.Fprintln(b.stdout(), filepath.Join(d, name))
+		}
+		return err
+	}
+
+	s := b.Add(x.Name + " links stale")
+	s.Usage = `[DIR]`
+	s.Summary = `list links for commands that no longer exist`
+	s.Method = func(args ...string) error {
+		d, err := dir(args)
+		if err != nil {
+			return err
+		}
+		stale, err := b.StaleLinks(d)
+		for _, name := range stale {
+			fmt.Fprintln(b.stdout(), filepath.Join(d, name))
+		}
+		return err
+	}
+
+	c := b.Add(x.Name + " links complete")
+	c.Usage = `[DIR]`
+	c.Summary = `print bash completion lines for each link`
+	c.Method = func(args ...string) error {
+		d, err := dir(args)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(b.stdout(), b.CompleteLines(d))
+		return nil
+	}
+
+	x.UpdateUsage()
+}
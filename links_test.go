@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleLink() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	dir, _ := os.MkdirTemp("", "cmdbox-links")
+	defer os.RemoveAll(dir)
+	os.WriteFile(filepath.Join(dir, "other"), []byte("mine"), 0644)
+
+	cmdbox.Add("greet")
+	cmdbox.Add("greet french")
+	cmdbox.Add("other")
+	x := cmdbox.Add("toolbox")
+	x.AddLinks()
+
+	linked, err := cmdbox.Link(dir, false)
+	fmt.Println(linked)
+	fmt.Println(strings.Replace(err.Error(), dir, "DIR", 1))
+
+	cmdbox.Delete("greet")
+	fmt.Println(cmdbox.StaleLinks(dir))
+
+	fmt.Print(strings.ReplaceAll(cmdbox.CompleteLines("/usr/local/bin"),
+		"/usr/local/bin/", ""))
+
+	fmt.Println(cmdbox.Unlink(dir))
+	entries, _ := os.ReadDir(dir)
+	fmt.Println(len(entries))
+
+	// Output:
+	// [greet toolbox]
+	// not replacing existing files: DIR/other
+	// [greet] <nil>
+	// complete -C other other
+	// complete -C toolbox toolbox
+	// [greet toolbox] <nil>
+	// 1
+}
+
+func ExampleBox_CompleteLines() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	cmdbox.Add("notinbox")
+
+	b := cmdbox.NewBox()
+	b.Add("greet")
+	x := b.Add("toolbox")
+	x.AddLinks()
+
+	b.Call(nil, "toolbox", "links", "complete", "/bin")
+
+	// Output:
+	// complete -C /bin/greet greet
+	// complete -C /bin/toolbox toolbox
+}
+
+func ExampleUnlink_mainSymlink() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	dir, _ := os.MkdirTemp("", "cmdbox-links")
+	defer os.RemoveAll(dir)
+
+	// installed by a package manager as a link to the executable
+	exe, _ := os.Executable()
+	exe, _ = filepath.EvalSymlinks(exe)
+	os.Symlink(exe, filepath.Join(dir, "toolbox"))
+
+	b := cmdbox.NewBox()
+	b.Add("greet")
+	b.Add("old")
+	b.Main = b.Add("toolbox")
+
+	fmt.Println(b.Link(dir, false))
+	b.Delete("old")
+	b.Delete("toolbox")
+	fmt.Println(b.StaleLinks(dir))
+	fmt.Println(b.Unlink(dir))
+	_, err := os.Lstat(filepath.Join(dir, "toolbox"))
+	fmt.Println(err)
+
+	// Output:
+	// [greet old] <nil>
+	// [old] <nil>
+	// [greet old] <nil>
+	// <nil>
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+// Middleware wraps the resolved Method (next) of the Command about to be
+// called (inv.Command) returning a new Method that usually does
+// something before or after calling next (timing, audit logging,
+// authorization checks, panic reporting, and such). The caller and
+// everything else about the call are available from the Invocation
+// and the arguments are those passed to the returned Method. Returning
+// without calling next prevents the Command from running at all. See
+// Use and Command.Middleware.
+//
+//    cmdbox.Use(func(inv *cmdbox.Invocation, next cmdbox.Method) cmdbox.Method {
+//      return func(args ...string) error {
+//        start := time.Now()
+//        defer func() { log.Println(inv.Command.Name, time.Since(start)) }()
+//        return next(args...)
+//      }
+//    })
+//
+type Middleware func(inv *Invocation, next Method) Method
+
+// Hook is a function to be used for Command.Pre and Command.Post values.
+// It is passed the Invocation of the Command about to be (or just)
+// called (inv.Command), which may be a descendant of the Command with
+// the hook, with its arguments as inv.Args. For Post hooks err is the
+// error returned by the Method (or nil) and the error returned by the
+// hook replaces it. For Pre hooks err is always nil and returning an
+// error prevents the Method from being called.
+//
+type Hook func(inv *Invocation, err error) error
+
+var middleware []Middleware
+
+// Use adds global Middleware to be applied to every Command called with
+// Call (and therefore Execute). Middleware is applied in the order
+// added with the first added being the outermost. Global Middleware is
+// always applied outside of any Command.Middleware. Init removes all
+// global Middleware.
+//
+func Use(m ...Middleware) { Default.Use(m...) }
+
+// wrap applies the global middleware, then the Middleware and Pre and
+// Post hooks of each Command in the inv.Path (from the outermost
+// ancestor to the called Command itself) to the method. The innermost
+// Method updates inv.Args to those actually passed.
+func wrap(inv *Invocation, method Method) Method {
+	path := inv.Path
+	hooked := func(args ...string) error {
+		inv.Args = args
+		for _, c := range path {
+			if c.Pre == nil {
+				continue
+			}
+			if err := c.Pre(inv, nil); err != nil {
+				return err
+			}
+		}
+		err := method(args...)
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i].Post != nil {
+				err = path[i].Post(inv, err)
+			}
+		}
+		return err
+	}
+
+	var all []Middleware
+	all = append(all, inv.Box.uses()...)
+	for _, c := range path {
+		all = append(all, c.Middleware...)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		hooked = all[i](inv, hooked)
+	}
+	return hooked
+}
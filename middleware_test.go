@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleUse() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("greet")
+	x.Method = func(args ...string) error {
+		fmt.Println("hello", args)
+		return nil
+	}
+
+	cmdbox.Use(func(inv *cmdbox.Invocation, next cmdbox.Method) cmdbox.Method {
+		return func(args ...string) error {
+			fmt.Println("before", inv.Command.Name)
+			err := next(args...)
+			fmt.Println("after", inv.Command.Name)
+			return err
+		}
+	})
+
+	cmdbox.Call(nil, "greet", "there")
+
+	// Output:
+	// before greet
+	// hello [there]
+	// after greet
+}
+
+func ExampleHook() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "bar")
+	x.Pre = func(inv *cmdbox.Invocation, _ error) error {
+		fmt.Println("foo pre", inv.Command.Name, inv.Args)
+		if len(inv.Args) > 0 && inv.Args[0] == "deny" {
+			return fmt.Errorf("denied")
+		}
+		return nil
+	}
+	x.Post = func(inv *cmdbox.Invocation, err error) error {
+		fmt.Println("foo post", inv.Command.Name, err)
+		return err
+	}
+	x.Middleware = append(x.Middleware,
+		func(inv *cmdbox.Invocation, next cmdbox.Method) cmdbox.Method {
+			return func(args ...string) error {
+				fmt.Println("foo middleware", inv.Command.Name)
+				return next(args...)
+			}
+		})
+
+	b := cmdbox.Add("foo bar")
+	b.Pre = func(inv *cmdbox.Invocation, _ error) error {
+		fmt.Println("bar pre")
+		return nil
+	}
+	b.Method = func(args ...string) error {
+		fmt.Println("bar", args)
+		return fmt.Errorf("oops")
+	}
+
+	fmt.Println(cmdbox.Call(nil, "foo", "bar", "some"))
+	fmt.Println(cmdbox.Call(nil, "foo", "bar", "deny"))
+
+	// Output:
+	// foo middleware foo bar
+	// foo pre foo bar [some]
+	// bar pre
+	// bar [some]
+	// foo post foo bar oops
+	// oops
+	// foo middleware foo bar
+	// foo pre foo bar [deny]
+	// denied
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Origin records where a Command was registered (see Add): the import
+// path of the package that called Add (or Set), the name of the file
+// containing the call, and the module (and version, if released)
+// containing that package according to the build information of the
+// executable. Any of these may be empty when they cannot be determined.
+//
+type Origin struct {
+	Package string `json:"package,omitempty" yaml:",omitempty"`
+	File    string `json:"file,omitempty" yaml:",omitempty"`
+	Module  string `json:"module,omitempty" yaml:",omitempty"`
+	Version string `json:"version,omitempty" yaml:",omitempty"`
+}
+
+// String returns the Origin as a single line suitable for logging.
+func (o *Origin) String() string {
+	if o == nil || o.Package == "" {
+		return "<unknown origin>"
+	}
+	buf := o.Package
+	if o.File != "" {
+		buf += " (" + o.File + ")"
+	}
+	if o.Module != "" && o.Module != o.Package {
+		buf += " in " + o.Module
+	}
+	if o.Version != "" {
+		buf += " " + o.Version
+	}
+	return buf
+}
+
+// Namespace returns a valid Command name word for the Module of the
+// Origin (or Package if no Module) made of the lowercase letters of its
+// last path element (skipping any major version suffix such as v2).
+// See NamespaceDups.
+//
+func (o *Origin) Namespace() string {
+	if o == nil {
+		return ""
+	}
+	p := o.Module
+	if p == "" {
+		p = o.Package
+	}
+	base := path.Base(p)
+	if len(base) > 1 && base[0] == 'v' &&
+		strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(p))
+	}
+	var ns string
+	for _, r := range strings.ToLower(base) {
+		if r >= 'a' && r <= 'z' {
+			ns += string(r)
+		}
+	}
+	return ns
+}
+
+// pkgpath is the import path of this package (skipped when looking for
+// the caller that registered a Command).
+var pkgpath = reflect.TypeOf((*Command)(nil)).Elem().PkgPath()
+
+var buildinfo struct {
+	once sync.Once
+	mods []*debug.Module
+}
+
+// modules returns the main module and all dependencies from the build
+// information (read only once).
+func modules() []*debug.Module {
+	buildinfo.once.Do(func() {
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		buildinfo.mods = append([]*debug.Module{&bi.Main}, bi.Deps...)
+	})
+	return buildinfo.mods
+}
+
+// funcpkg returns the package import path from a fully qualified
+// function name (as reported by runtime.Frame) including methods such
+// as pkg.(*T).M. Dots in the last element of the path are escaped (as
+// %2e, sometimes more than once) by the toolchain so the first dot
+// left ends the path. Should they not be, the longest module path from
+// the build information followed by a dot is used instead (so that
+// gopkg.in/yaml.v2.Func is still in gopkg.in/yaml.v2).
+func funcpkg(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[slash+1:], ".")
+	if dot < 0 {
+		return fn
+	}
+	pkg := fn[:slash+1+dot]
+	if strings.Contains(pkg, "%") {
+		for strings.Contains(pkg, "%") {
+			p, err := url.PathUnescape(pkg)
+			if err != nil || p == pkg {
+				break
+			}
+			pkg = p
+		}
+		return pkg
+	}
+	for _, m := range modules() {
+		if len(m.Path) > len(pkg) && strings.HasPrefix(fn, m.Path+".") {
+			pkg = m.Path
+		}
+	}
+	return pkg
+}
+
+// origin returns the Origin of the first caller outside of this package.
+func origin() *Origin {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		f, more := frames.Next()
+		pkg := funcpkg(f.Function)
+		if pkg != pkgpath && pkg != "runtime" {
+			o := &Origin{Package: pkg, File: filepath.Base(f.File)}
+			pkg = strings.TrimSuffix(pkg, "_test") // external tests
+			var best *debug.Module
+			for _, m := range modules() {
+				if (pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/")) &&
+					(best == nil || len(m.Path) > len(best.Path)) {
+					best = m
+				}
+			}
+			if best != nil {
+				if best.Replace != nil {
+					best = best.Replace
+				}
+				o.Module = best.Path
+				if best.Version != "(devel)" {
+					o.Version = best.Version
+				}
+			}
+			return o
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// Conflicts returns a line for each of the Dups naming the Origin of
+// the duplicate and that of the Command it duplicates so that it is
+// clear which imported packages are responsible. See NamespaceDups and
+// Rename.
+//
+func Conflicts() []string { return Default.Conflicts() }
+
+// Conflicts is the Box equivalent of the package Conflicts function.
+func (b *Box) Conflicts() []string {
+	lines := []string{}
+	for _, dup := range b.Dups() {
+		name := strings.TrimRight(dup, "_")
+		var first *Origin
+		if x := b.Get(name); x != nil {
+			first = x.Origin
+		}
+		lines = append(lines, fmt.Sprintf("%v from %v duplicates %v from %v",
+			dup, b.Get(dup).Origin, name, first))
+	}
+	return lines
+}
+
+// namespaced returns the name for x in the register when NamespaceDups
+// is enabled: the name prefixed with the Namespace of its Origin (and
+// a dot) if the name is already registered by a different module or
+// its first word has already been namespaced for the same module (so
+// that subcommands follow). Otherwise, the name is returned unchanged.
+func (b *Box) namespaced(name string, o *Origin) string {
+	ns := o.Namespace()
+	if ns == "" {
+		return name
+	}
+	first := strings.SplitN(name, " ", 2)[0]
+	b.nsmu.Lock()
+	defer b.nsmu.Unlock()
+	key := o.Module + " " + first
+	if b.namespaces[key] {
+		return ns + "." + name
+	}
+	x := b.reg().Get(name)
+	if x == nil || x.Origin == nil || o.Module == "" ||
+		x.Origin.Module == o.Module {
+		return name
+	}
+	if b.namespaces == nil {
+		b.namespaces = map[string]bool{}
+	}
+	b.namespaces[key] = true
+	return ns + "." + name
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleOrigin() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	fmt.Println(cmdbox.Add("foo").Origin)
+
+	// Output:
+	// github.com/rwxrob/cmdbox_test (origin_test.go) in github.com/rwxrob/cmdbox
+}
+
+func ExampleOrigin_dottedPackage() {
+	for _, fn := range []string{
+		"github.com/rwxrob/cmdbox.Add",
+		"github.com/rwxrob/cmdbox.(*Box).Add",
+		"example.com/dotmod/sub%2ev2.F",
+		"example.com/dotmod/sub%2ev2.(*T).M",
+		"example.com/dotmod/sub%252ev2.G.func1",
+		"gopkg.in/yaml%2ev2.Marshal",
+		"gopkg.in/yaml.v2.Marshal", // unescaped but a known module
+		"main.main",
+	} {
+		fmt.Println(cmdbox.FuncPkg(fn))
+	}
+
+	// Output:
+	// github.com/rwxrob/cmdbox
+	// github.com/rwxrob/cmdbox
+	// example.com/dotmod/sub.v2
+	// example.com/dotmod/sub.v2
+	// example.com/dotmod/sub.v2
+	// gopkg.in/yaml.v2
+	// gopkg.in/yaml.v2
+	// main
+}
+
+func ExampleOrigin_Namespace() {
+	for _, mod := range []string{
+		"github.com/someone/tools",
+		"github.com/someone/cmdbox-greet/v2",
+		"example.com/My_Stuff",
+	} {
+		fmt.Println((&cmdbox.Origin{Module: mod}).Namespace())
+	}
+
+	// Output:
+	// tools
+	// cmdboxgreet
+	// mystuff
+}
+
+func ExampleConflicts() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	other := cmdbox.NewCommand("foo")
+	other.Origin = &cmdbox.Origin{
+		Package: "github.com/someone/tools/cmd",
+		File:    "foo.go",
+		Module:  "github.com/someone/tools",
+		Version: "v1.2.0",
+	}
+	cmdbox.Set("foo", other)
+	cmdbox.Add("foo")
+
+	for _, line := range cmdbox.Conflicts() {
+		fmt.Println(line)
+	}
+
+	// Output:
+	// foo_ from github.com/rwxrob/cmdbox_test (origin_test.go) in github.com/rwxrob/cmdbox duplicates foo from github.com/someone/tools/cmd (foo.go) in github.com/someone/tools v1.2.0
+}
+
+func ExampleBox_NamespaceDups() {
+	b := cmdbox.NewBox()
+	b.NamespaceDups = true
+
+	other := cmdbox.NewCommand("foo")
+	other.Origin = &cmdbox.Origin{Module: "github.com/someone/tools"}
+	b.Set("foo", other)
+
+	b.Add("foo", "bar")
+	bar := b.Add("foo bar")
+	bar.Method = func(args ...string) error {
+		fmt.Println("bar called")
+		return nil
+	}
+	b.Add("foo") // same module, still a dup
+
+	fmt.Println(b.Names())
+	b.Call(nil, "cmdbox.foo", "bar")
+
+	// Output:
+	// [cmdbox.foo cmdbox.foo bar cmdbox.foo_ foo]
+	// bar called
+}
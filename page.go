@@ -0,0 +1,70 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"bytes"
+
+	"github.com/rwxrob/cmdbox/term"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// Page prints buf through the user's pager when standard output is an
+// interactive terminal and buf is longer than the terminal (see
+// util.Page for details). Unless color is enabled (see Color and
+// ForceColor) all terminal escapes are removed from buf first (except
+// hyperlinks when forced, see term.HyperlinksForced). Set the
+// CMDBOX_NOPAGER environment variable (or util.PagerOff) to disable
+// paging entirely. Also see PageWriter and Command.PrintHelp.
+//
+func Page(buf string) error {
+	if !colorOn() {
+		buf = stripEsc(buf)
+	}
+	return util.Page(buf)
+}
+
+// stripEsc removes the terminal escapes from buf keeping hyperlinks if
+// forced (see term.HyperlinksForced).
+func stripEsc(buf string) string {
+	if term.HyperlinksForced() {
+		return util.StripEscKeepLinks(buf)
+	}
+	return util.StripEsc(buf)
+}
+
+// PageWriter is an opt-in io.WriteCloser for Methods that produce
+// potentially long output and wish to have it paged exactly like
+// help documentation. Everything written is buffered and then passed
+// to Page when Close is called.
+//
+//    x.Method = func(args ...string) error {
+//      out := new(cmdbox.PageWriter)
+//      defer out.Close()
+//      fmt.Fprintln(out, "lots of output")
+//      return nil
+//    }
+//
+type PageWriter struct {
+	bytes.Buffer
+}
+
+// Close pages everything written so far and resets the buffer.
+func (w *PageWriter) Close() error {
+	defer w.Reset()
+	return Page(w.String())
+}
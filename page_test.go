@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExamplePageWriter() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	// never paged during testing since not an interactive terminal
+	out := new(cmdbox.PageWriter)
+	fmt.Fprintln(out, "some")
+	fmt.Fprintln(out, "\033[1mbold\033[0m output")
+	out.Close()
+
+	// Output:
+	// some
+	// bold output
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwxrob/cmdbox/comp"
+	"github.com/rwxrob/cmdbox/util"
+	"github.com/rwxrob/cmdbox/valid"
+)
+
+// PluginsOff disables all lookup of external plugin executables (see
+// Plugin). It is set at init() time if the CMDBOX_NOPLUGINS environment
+// variable is set to anything.
+//
+var PluginsOff bool
+
+// PluginsDir is a directory searched for plugin executables before
+// those in the PATH (see Plugin). It is set at init() time from the
+// CMDBOX_PLUGINS environment variable (if any).
+//
+var PluginsDir string
+
+// PluginTimeout is the longest a plugin is given to respond with its
+// summary (see Plugin.Summary) or completion.
+//
+var PluginTimeout = 2 * time.Second
+
+func init() {
+	PluginsOff = os.Getenv("CMDBOX_NOPLUGINS") != ""
+	PluginsDir = os.Getenv("CMDBOX_PLUGINS")
+}
+
+// Plugin is an external executable that is called as if it were
+// a subcommand when no registered Command matches (in the style of git
+// and kubectl). Plugins are found in PluginsDir and then the PATH by
+// joining the Command name and the subcommand words with dashes. For
+// example, with a main command of foo
+//
+//     foo bar baz some
+//
+// first looks for a foo-bar-baz executable (passing it "some") and then
+// a foo-bar executable (passing it "baz some") if neither bar nor baz
+// are registered Commands. Only valid Command names (see valid.Name)
+// are considered (with or without an .exe suffix). Plugins are only
+// looked up for Commands without a Default since the Default receives
+// any arguments that are not registered subcommands (so that nothing
+// on the PATH can take over such arguments by accident). The help and
+// shell Defaults set by AddHelp and AddShell do not count since they
+// never take such arguments as their own (and plugins are looked up
+// before falling back to them).
+//
+// Plugins may be written in any language. By convention a plugin
+// prints a one line summary for help listings when called with the
+// single argument "summary" and prints completion candidates (one per
+// line) when COMP_LINE is set (with the line rewritten to begin with
+// the plugin name just as if it were called directly).
+//
+type Plugin struct {
+	Name string `json:"name"` // foo-bar-baz
+	Path string `json:"path"`
+}
+
+// pluginDirs returns PluginsDir followed by the directories of the PATH.
+func pluginDirs() []string {
+	dirs := []string{}
+	if PluginsDir != "" {
+		dirs = append(dirs, PluginsDir)
+	}
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// isExec returns true if path is an executable regular file.
+func isExec(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
+
+// builtinDefault returns true if the Default of the Command is the help
+// or shell subcommand (see AddHelp and AddShell).
+func (x *Command) builtinDefault() bool {
+	return x.Default == "help" || x.Default == "shell"
+}
+
+// pluginPrefix returns the Name of the Command joined with dashes.
+func (x *Command) pluginPrefix() string {
+	return strings.ReplaceAll(x.Name, " ", "-")
+}
+
+// LookPlugin returns the Plugin matching the most leading words of args
+// along with the remaining args, or nil and args unchanged if none is
+// found (see Plugins).
+//
+func (x *Command) LookPlugin(args []string) (*Plugin, []string) {
+	plugins := x.Plugins()
+	if len(plugins) == 0 {
+		return nil, args
+	}
+	n := 0
+	for n < len(args) && valid.Name(args[n]) {
+		n++
+	}
+	for ; n > 0; n-- {
+		name := x.pluginPrefix() + "-" + strings.Join(args[:n], "-")
+		for _, p := range plugins {
+			if p.Name == name {
+				return &p, args[n:]
+			}
+		}
+	}
+	return nil, args
+}
+
+// pluginCache holds the Plugins found for each prefix and search path
+// and the Summary of each plugin executable so that the PATH is only
+// scanned (and each plugin only asked for its summary) once per
+// process.
+var pluginCache = struct {
+	sync.Mutex
+	found   map[string][]Plugin
+	summary map[string]string
+}{found: map[string][]Plugin{}, summary: map[string]string{}}
+
+// Plugins returns the sorted Plugins found for the Command (see
+// Plugin) or none if PluginsOff is set (for the Box) or the Command has
+// a Default (other than help or shell, see builtinDefault). When more
+// than one plugin executable has the same name only the first found is
+// included. The directories are only searched the first time
+// (for a given PluginsDir and PATH).
+//
+func (x *Command) Plugins() []Plugin {
+	if x.Box().pluginsOff() || (x.Default != "" && !x.builtinDefault()) {
+		return []Plugin{}
+	}
+	dirs := pluginDirs()
+	key := x.pluginPrefix() + string(filepath.ListSeparator) +
+		strings.Join(dirs, string(filepath.ListSeparator))
+	pluginCache.Lock()
+	defer pluginCache.Unlock()
+	if plugins, has := pluginCache.found[key]; has {
+		return plugins
+	}
+	plugins := []Plugin{}
+	prefix := x.pluginPrefix() + "-"
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := strings.TrimSuffix(e.Name(), ".exe")
+			if !strings.HasPrefix(name, prefix) || seen[name] {
+				continue
+			}
+			if !valid.Name(strings.ReplaceAll(name[len(prefix):], "-", "")) {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExec(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{name, path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	pluginCache.found[key] = plugins
+	return plugins
+}
+
+// Sub returns the subcommand words of the Plugin for the given Command
+// (baz for foo-bar-baz and foo bar).
+//
+func (p Plugin) Sub(x *Command) string {
+	sub := strings.TrimPrefix(p.Name, x.pluginPrefix()+"-")
+	return strings.ReplaceAll(sub, "-", " ")
+}
+
+// Method returns a Method that runs the plugin with the arguments
+// passed to it (see util.Run).
+//
+func (p Plugin) Method() Method {
+	return func(args ...string) error {
+		return util.Run(append([]string{p.Path}, args...)...)
+	}
+}
+
+// output runs the plugin with the args and extra environment and
+// returns the lines of its standard output.
+func (p Plugin) output(env []string, args ...string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), PluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.Path, args...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		if l := strings.TrimSpace(s.Text()); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+// Summary returns the first line printed by the plugin when called
+// with the "summary" argument (or an empty string if it fails). The
+// plugin is only called the first time. Summary is only used for help
+// (never during completion).
+//
+func (p Plugin) Summary() string {
+	pluginCache.Lock()
+	summary, has := pluginCache.summary[p.Path]
+	pluginCache.Unlock()
+	if has {
+		return summary
+	}
+	lines, err := p.output([]string{"COMP_LINE="}, "summary")
+	if err == nil && len(lines) > 0 {
+		summary = lines[0]
+	}
+	pluginCache.Lock()
+	pluginCache.summary[p.Path] = summary
+	pluginCache.Unlock()
+	return summary
+}
+
+// Complete returns the completion candidates printed by the plugin
+// when called with COMP_LINE set to the line (which should begin with
+// the plugin Name).
+//
+func (p Plugin) Complete(line string) []string {
+	lines, err := p.output([]string{"COMP_LINE=" + line})
+	if err != nil {
+		return []string{}
+	}
+	return lines
+}
+
+// completePlugin delegates completion to a Plugin if the words of the
+// current completion line (see comp.Args) after the Command name match
+// one and do not match a registered Command. The line passed to the
+// plugin begins with its own name followed by the remaining words.
+func (x *Command) completePlugin() ([]string, bool) {
+	args := comp.Args()
+	if len(args) < 3 {
+		return nil, false
+	}
+	words := args[1 : len(args)-1]
+	if name, _ := x.Expand(words[0]); name != "" {
+		return nil, false
+	}
+	p, rest := x.LookPlugin(words)
+	if p == nil {
+		return nil, false
+	}
+	line := strings.Join(append(append([]string{p.Name}, rest...),
+		args[len(args)-1]), " ")
+	if args[len(args)-1] == " " {
+		line = strings.TrimSuffix(line, " ") + " "
+	}
+	return p.Complete(line), true
+}
+
+// pluginTitles returns the plugins and their summaries formatted for
+// help output (see Titles). The plugins are asked for their summaries
+// concurrently so that help never waits more than PluginTimeout.
+func (x *Command) pluginTitles(indent int) string {
+	plugins := x.Plugins()
+	summaries := make([]string, len(plugins))
+	var wg sync.WaitGroup
+	for i, p := range plugins {
+		wg.Add(1)
+		go func(i int, p Plugin) {
+			defer wg.Done()
+			summaries[i] = p.Summary()
+		}(i, p)
+	}
+	wg.Wait()
+	limit := 0
+	for _, p := range plugins {
+		if n := len(p.Sub(x)); n > limit {
+			limit = n
+		}
+	}
+	var buf string
+	for i, p := range plugins {
+		sub := p.Sub(x)
+		pad := fmt.Sprintf("%-*v - ", limit, sub)
+		buf += x.paint(x.Box().theme().Name, sub) + pad[len(sub):] +
+			truncate(summaries[i], HelpWidth()-indent-len(pad)) + "\n"
+	}
+	return util.Indent(buf, indent)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/comp"
+)
+
+// plugins creates a temporary PluginsDir with the named shell scripts
+// and returns a function to remove it.
+func plugins(scripts map[string]string) func() {
+	dir, _ := os.MkdirTemp("", "cmdbox-plugins")
+	for name, body := range scripts {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755)
+	}
+	prev := cmdbox.PluginsDir
+	cmdbox.PluginsDir = dir
+	return func() {
+		cmdbox.PluginsDir = prev
+		os.RemoveAll(dir)
+	}
+}
+
+func ExamplePlugin() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	defer plugins(map[string]string{
+		"plugtest-hello": `
+			[ "$1" = summary ] && echo say hello && exit
+			[ -n "$COMP_LINE" ] && echo world && echo there && exit
+			echo hello "$@"`,
+		"plugtest-hello-deep": `echo deep "$@"`,
+	})()
+
+	x := cmdbox.Add("plugtest", "other")
+	x.Summary = "plugin test"
+
+	cmdbox.Call(nil, "plugtest", "hello", "there")
+	cmdbox.Call(nil, "plugtest", "hello", "deep", "down")
+
+	for _, p := range x.Plugins() {
+		fmt.Printf("%v: %v\n", p.Sub(x), p.Summary())
+	}
+
+	comp.This = "plugtest "
+	defer func() { comp.This = "" }()
+	x.Complete()
+
+	comp.This = "plugtest hello "
+	x.Complete()
+
+	// Output:
+	// hello there
+	// deep down
+	// hello: say hello
+	// hello deep: deep summary
+	// hello
+	// other
+	// world
+	// there
+}
+
+func ExamplePlugin_help() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	defer plugins(map[string]string{
+		"plugtest-hello": `
+			[ "$1" = summary ] && echo say hello && exit
+			echo hello "$@"`,
+	})()
+
+	x := cmdbox.Add("plugtest")
+	x.AddHelp() // sets the Default to help
+
+	cmdbox.Call(nil, "plugtest", "hello", "there")
+	fmt.Println(strings.Contains(x.Help(), "say hello"))
+
+	comp.This = "plugtest "
+	defer func() { comp.This = "" }()
+	x.Complete()
+
+	// Output:
+	// hello there
+	// true
+	// hello
+	// help
+}
+
+func ExampleCommand_LookPlugin() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	defer plugins(map[string]string{
+		"plugtest-win.exe":  `echo win "$@"`,
+		"plugtest-file.txt": `echo took over`,
+		"deftest-file.txt":  `echo took over`,
+	})()
+
+	x := cmdbox.Add("plugtest")
+	p, rest := x.LookPlugin([]string{"win", "some"})
+	fmt.Println(p.Name, filepath.Base(p.Path), rest)
+
+	d := cmdbox.Add("deftest", "run")
+	d.Default = "run"
+	run := cmdbox.Add("deftest run")
+	run.Method = func(args ...string) error {
+		fmt.Println("run", args)
+		return nil
+	}
+	cmdbox.Call(nil, "deftest", "file.txt")
+	fmt.Println(len(d.Plugins()))
+
+	// Output:
+	// plugtest-win plugtest-win.exe [some]
+	// run [file.txt]
+	// 0
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/comp"
+	"github.com/rwxrob/cmdbox/term"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// ShellReadLine is called by Command.Shell to print the prompt and read
+// each line of input. It must return io.EOF when there is no more input
+// (Ctrl-D). The history passed is that loaded from the ShellHistory file
+// (oldest first) and complete returns the completions for the line
+// typed so far (see Command.CompleteLine). The default uses
+// a term.LineEditor (with history and tab completion) when standard
+// input is an interactive terminal and otherwise simply reads plain
+// lines without prompting. Either way, input is read through the same
+// buffered reader as the term prompts (see term.StdinEditor) so that
+// commands prompting for input see what follows. Assign another line
+// editor here to change this. The same term.LineEditor is used for
+// every line of a Shell session (keeping its kill ring).
+//
+var ShellReadLine = func(prompt string, history []string,
+	complete func(line string) []string) (string, error) {
+	e := shellEditor
+	if e == nil {
+		e = term.StdinEditor()
+	}
+	e.History, e.Complete = history, complete
+	line, err := e.ReadLine(prompt)
+	if err == term.ErrInterrupt {
+		return "", nil
+	}
+	return line, err
+}
+
+// shellEditor is the line editor of the current Shell session (if any)
+// used by the default ShellReadLine.
+var shellEditor *term.LineEditor
+
+// ShellPrompt returns the prompt printed by Command.Shell for each line.
+var ShellPrompt = func(x *Command) string { return x.Name + "> " }
+
+// AddShell adds a shell subcommand that starts an interactive shell (see
+// Shell) and sets x.Default to it if unset so that calling the command
+// alone enters the shell. Call AddShell after AddHelp if help should
+// remain the default instead. Any unknown arguments (that fall through
+// to the default) produce a usage error rather than entering the shell.
+//
+func (x *Command) AddShell() {
+	x.Add("shell")
+	if x.Default == "" {
+		x.Default = "shell"
+	}
+	s := x.Box().Add(x.Name + " shell")
+	s.Summary = `start an interactive shell`
+	s.Description = `
+		Starts an interactive shell in which each line entered is run as
+		a command as if it had been typed after the main command itself.
+		Enter exit or quit (or press Ctrl-D) to leave the shell.`
+	s.Method = func(args ...string) error {
+		if len(args) > 0 {
+			return x.UsageError()
+		}
+		return x.Shell()
+	}
+	x.UpdateUsage()
+}
+
+// Shell starts an interactive read-eval-print loop for x. Each line read
+// with ShellReadLine is split into arguments (see util.SplitArgs) and
+// dispatched with Call using Main (or x itself if Main has not been set)
+// as the caller and x.Name as the name so that every subcommand, alias,
+// and default of x is available. Errors are logged and the shell
+// continues. Blank lines are ignored. Entering exit or quit, or the end
+// of input (Ctrl-D), returns nil leaving any actual exiting to the caller
+// (see Exit and DoNotExit). Each line is appended to the ShellHistory
+// file.
+//
+func (x *Command) Shell() error {
+	caller := x.Box().main()
+	if caller == nil {
+		caller = x
+	}
+	defer func(prev *term.LineEditor) { shellEditor = prev }(shellEditor)
+	shellEditor = term.StdinEditor()
+	history := ShellHistory(x)
+	lines := readHistory(history)
+	for {
+		line, err := ShellReadLine(ShellPrompt(x), lines, x.CompleteLine)
+		args := util.SplitArgs(line)
+		if err != nil && len(args) == 0 {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+		lines = append(lines, strings.TrimSpace(line))
+		appendHistory(history, strings.TrimSpace(line))
+		if err := x.Box().Call(caller, x.Name, args...); err != nil {
+			x.Box().logError(err)
+		}
+	}
+}
+
+// ShellHistory returns the path to the file in which Shell saves the
+// history of lines entered for the given Command. The XDG_STATE_HOME
+// environment variable is observed (with ~/.local/state as the default)
+// and the file is named history within a directory named after the
+// Command. An empty string (no history) is returned if the home
+// directory cannot be determined.
+//
+func ShellHistory(x *Command) string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, strings.ReplaceAll(x.Name, " ", "-"), "history")
+}
+
+func readHistory(path string) []string {
+	lines := []string{}
+	if path == "" {
+		return lines
+	}
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return lines
+	}
+	for _, line := range strings.Split(string(byt), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func appendHistory(path, line string) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// CompleteLine returns the completions for a line typed after x (as
+// in an interactive Shell) by following each complete word through the
+// subcommands of x (see Expand) and then completing with the CompFunc
+// of the last Command found (or DefaultComplete). Note that comp.This
+// is temporarily set during completion.
+//
+func (x *Command) CompleteLine(line string) []string {
+	c := x
+	words := strings.Fields(line)
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		words = words[:len(words)-1]
+	}
+	for _, word := range words {
+		name, _ := c.Expand(word)
+		sub := c.Resolve(name)
+		if name == "" || sub == nil {
+			break
+		}
+		c = sub
+	}
+	c.load()
+	defer func(this string) { comp.This = this }(comp.This)
+	comp.This = x.Name + " " + line
+	switch {
+	case c.CompFunc != nil:
+		return c.CompFunc(c)
+	case DefaultComplete != nil:
+		return DefaultComplete(c)
+	}
+	return []string{}
+}
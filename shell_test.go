@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/comp"
+	"github.com/rwxrob/cmdbox/term"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleCommand_Shell() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	state, _ := os.MkdirTemp("", "")
+	defer os.RemoveAll(state)
+	os.Setenv("XDG_STATE_HOME", state)
+	defer os.Unsetenv("XDG_STATE_HOME")
+
+	x := cmdbox.Add("foo", "g|greet")
+	x.AddShell()
+	g := cmdbox.Add("foo greet")
+	g.Method = func(args ...string) error {
+		fmt.Printf("hello %q\n", args)
+		return nil
+	}
+
+	util.MockStdin("greet 'Mr. Rob'\n\n  g\nbork\n")
+	defer util.UnmockStdin()
+	cmdbox.Call(nil, "foo") // default is shell
+
+	byt, _ := os.ReadFile(filepath.Join(state, "foo", "history"))
+	fmt.Print(string(byt))
+
+	// Output:
+	// hello ["Mr. Rob"]
+	// hello []
+	// usage: foo [g|greet|shell]
+	// greet 'Mr. Rob'
+	// g
+	// bork
+
+}
+
+func ExampleCommand_Shell_exit() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	os.Setenv("XDG_STATE_HOME", os.DevNull)
+	defer os.Unsetenv("XDG_STATE_HOME")
+
+	x := cmdbox.Add("foo", "greet")
+	g := cmdbox.Add("foo greet")
+	g.Method = func(args ...string) error {
+		fmt.Println("hello")
+		return nil
+	}
+
+	util.MockStdin("greet\nexit\ngreet\n")
+	defer util.UnmockStdin()
+	fmt.Println(x.Shell())
+
+	// Output:
+	// hello
+	// <nil>
+}
+
+func ExampleCommand_Shell_prompt() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	os.Setenv("XDG_STATE_HOME", os.DevNull)
+	defer os.Unsetenv("XDG_STATE_HOME")
+
+	x := cmdbox.Add("foo", "ask")
+	a := cmdbox.Add("foo ask")
+	a.Method = func(args ...string) error {
+		name, err := term.Input("name? ", nil)
+		fmt.Printf("hello %q %v\n", name, err)
+		return nil
+	}
+
+	// the answer follows the command in the same piped input
+	util.MockStdin("ask\nRob\nask\nDoris\n")
+	defer util.UnmockStdin()
+	fmt.Println(x.Shell())
+
+	// Output:
+	// hello "Rob" <nil>
+	// hello "Doris" <nil>
+	// <nil>
+}
+
+func ExampleCommand_CompleteLine() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "greet", "go")
+	cmdbox.Add("foo greet", "fr|french", "russian")
+	defer func() { comp.This = "" }()
+
+	fmt.Println(x.CompleteLine(""))
+	fmt.Println(x.CompleteLine("g"))
+	fmt.Println(x.CompleteLine("greet "))
+	fmt.Println(x.CompleteLine("greet r"))
+
+	// Output:
+	// [go greet]
+	// [go greet]
+	// [french russian]
+	// [russian]
+}
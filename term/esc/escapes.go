@@ -7,12 +7,19 @@ const (
 	CS          = "\033[2J\033[H"
 	Bold        = "\033[1m"
 	B           = "\033[1m"
+	Dim         = "\033[2m"
+	D           = "\033[2m"
 	Italic      = "\033[3m"
 	I           = "\033[3m"
 	BoldItalic  = "\033[1m\033[3m"
 	BI          = "\033[1m\033[3m"
 	Underline   = "\033[4m"
 	U           = "\033[4m"
+	Blink       = "\033[5m"
+	Reverse     = "\033[7m"
+	R           = "\033[7m"
+	Strike      = "\033[9m"
+	S           = "\033[9m"
 	Black       = "\033[30m"
 	Red         = "\033[31m"
 	Green       = "\033[32m"
@@ -21,4 +28,23 @@ const (
 	Magenta     = "\033[35m"
 	Cyan        = "\033[36m"
 	White       = "\033[37m"
+	BgBlack     = "\033[40m"
+	BgRed       = "\033[41m"
+	BgGreen     = "\033[42m"
+	BgYellow    = "\033[43m"
+	BgBlue      = "\033[44m"
+	BgMagenta   = "\033[45m"
+	BgCyan      = "\033[46m"
+	BgWhite     = "\033[47m"
+)
+
+// Cursor and line control.
+const (
+	ClearLine     = "\033[2K\r"
+	ClearToEnd    = "\033[0K"
+	ClearBelow    = "\033[0J"
+	SaveCursor    = "\0337"
+	RestoreCursor = "\0338"
+	HideCursor    = "\033[?25l"
+	ShowCursor    = "\033[?25h"
 )
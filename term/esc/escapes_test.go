@@ -29,3 +29,32 @@ func Test_BoldItalic(t *testing.T) {
 	t.Log(BoldItalic + "bold_italic" + Reset)
 	t.Log(BI + "bold_italic" + X)
 }
+
+func Test_Style(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"bold":              Bold,
+		"Bold Red":          Bold + Red,
+		"yellow on blue":    Yellow + BgBlue,
+		"208":               "\033[38;5;208m",
+		"on 17":             "\033[48;5;17m",
+		"#ff8800":           "\033[38;2;255;136;0m",
+		"italic on #000010": Italic + "\033[48;2;0;0;16m",
+	}
+	for spec, want := range tests {
+		got, err := Style(spec)
+		if err != nil || got != want {
+			t.Errorf("%q: want %q got %q (%v)", spec, want, got, err)
+		}
+		t.Log(got + spec + Reset)
+	}
+	for _, spec := range []string{"purple", "256", "#fff", "on bold"} {
+		if _, err := Style(spec); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
+
+func Test_Link(t *testing.T) {
+	t.Log(Link("https://github.com/rwxrob/cmdbox", "cmdbox"))
+}
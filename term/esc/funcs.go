@@ -0,0 +1,129 @@
+package esc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color256 returns the foreground color escape for one of the 256
+// colors (0-255) of the extended terminal palette.
+func Color256(n int) string { return fmt.Sprintf("\033[38;5;%dm", n) }
+
+// Bg256 returns the background color escape for one of the 256 colors
+// (0-255) of the extended terminal palette.
+func Bg256(n int) string { return fmt.Sprintf("\033[48;5;%dm", n) }
+
+// RGB returns the 24-bit (truecolor) foreground color escape.
+func RGB(r, g, b int) string { return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b) }
+
+// BgRGB returns the 24-bit (truecolor) background color escape.
+func BgRGB(r, g, b int) string { return fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b) }
+
+// Up moves the cursor up n lines.
+func Up(n int) string { return fmt.Sprintf("\033[%dA", n) }
+
+// Down moves the cursor down n lines.
+func Down(n int) string { return fmt.Sprintf("\033[%dB", n) }
+
+// Right moves the cursor right n columns.
+func Right(n int) string { return fmt.Sprintf("\033[%dC", n) }
+
+// Left moves the cursor left n columns.
+func Left(n int) string { return fmt.Sprintf("\033[%dD", n) }
+
+// Move moves the cursor to the given row and column (starting at 1).
+func Move(row, col int) string { return fmt.Sprintf("\033[%d;%dH", row, col) }
+
+// Link returns text as an OSC 8 hyperlink to url. Terminals that do not
+// support OSC 8 hyperlinks usually just show the text.
+func Link(url, text string) string {
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
+
+var named = map[string]string{
+	"reset":     Reset,
+	"bold":      Bold,
+	"dim":       Dim,
+	"italic":    Italic,
+	"underline": Underline,
+	"blink":     Blink,
+	"reverse":   Reverse,
+	"strike":    Strike,
+	"black":     Black,
+	"red":       Red,
+	"green":     Green,
+	"yellow":    Yellow,
+	"blue":      Blue,
+	"magenta":   Magenta,
+	"cyan":      Cyan,
+	"white":     White,
+}
+
+var namedbg = map[string]string{
+	"black":   BgBlack,
+	"red":     BgRed,
+	"green":   BgGreen,
+	"yellow":  BgYellow,
+	"blue":    BgBlue,
+	"magenta": BgMagenta,
+	"cyan":    BgCyan,
+	"white":   BgWhite,
+}
+
+// Style returns the combined escapes for a human-friendly style
+// specification consisting of space separated words, each of which is
+// one of the following:
+//
+//     bold dim italic underline blink reverse strike
+//     black red green yellow blue magenta cyan white
+//     0-255      (256-color palette)
+//     #rrggbb    (24-bit truecolor)
+//
+// Any color may be preceded by the word "on" to make it a background
+// color instead ("bold yellow on blue", "208 on #202020"). An empty
+// specification returns an empty string. An error is returned for
+// anything not recognized.
+func Style(spec string) (string, error) {
+	var out string
+	bg := false
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if word == "on" {
+			bg = true
+			continue
+		}
+		s, err := style(word, bg)
+		if err != nil {
+			return "", err
+		}
+		out += s
+		bg = false
+	}
+	return out, nil
+}
+
+func style(word string, bg bool) (string, error) {
+	if bg {
+		if s, has := namedbg[word]; has {
+			return s, nil
+		}
+	} else if s, has := named[word]; has {
+		return s, nil
+	}
+	if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+		if bg {
+			return Bg256(n), nil
+		}
+		return Color256(n), nil
+	}
+	if len(word) == 7 && word[0] == '#' {
+		if v, err := strconv.ParseUint(word[1:], 16, 32); err == nil {
+			r, g, b := int(v>>16), int(v>>8&0xff), int(v&0xff)
+			if bg {
+				return BgRGB(r, g, b), nil
+			}
+			return RGB(r, g, b), nil
+		}
+	}
+	return "", fmt.Errorf("unknown style: %v", word)
+}
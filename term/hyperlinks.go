@@ -0,0 +1,44 @@
+package term
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HyperlinksForced returns true if FORCE_HYPERLINK is set to anything
+// but 0, in which case hyperlinks should be used no matter where output
+// is going or whether color is enabled.
+func HyperlinksForced() bool {
+	f := os.Getenv("FORCE_HYPERLINK")
+	return f != "" && f != "0"
+}
+
+// SupportsHyperlinks returns true if the terminal is known to support
+// OSC 8 hyperlinks (see esc.Link) based on the TERM, TERM_PROGRAM, and
+// other environment variables set by such terminals. Setting
+// FORCE_HYPERLINK to 1 (or 0) overrides detection entirely. Note that
+// whether output is to an interactive terminal is not checked (see
+// IsTerminal).
+func SupportsHyperlinks() bool {
+	if f := os.Getenv("FORCE_HYPERLINK"); f != "" {
+		return f != "0"
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	t := os.Getenv("TERM")
+	for _, name := range []string{"kitty", "foot", "alacritty", "wezterm"} {
+		if strings.Contains(t, name) {
+			return true
+		}
+	}
+	return false
+}
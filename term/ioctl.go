@@ -9,12 +9,37 @@
 package term
 
 import (
+	"os"
+	"os/signal"
 	"syscall"
 	"unsafe"
 )
 
-func init() {
-	syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(0), uintptr(syscall.TIOCGWINSZ),
-		uintptr(unsafe.Pointer(&WinSize)))
+// watchWinSize calls UpdateWinSize every time the terminal window is
+// resized (SIGWINCH) for the rest of the life of the process.
+func watchWinSize() {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	go func() {
+		for range resized {
+			UpdateWinSize()
+		}
+	}()
+}
+
+// winsize queries standard output and then standard input for the
+// terminal size returning false if neither are terminals.
+func winsize() (row, col uint16, ok bool) {
+	ws := struct {
+		Row, Col       uint16
+		Xpixel, Ypixel uint16
+	}{}
+	for _, fd := range []uintptr{1, 0} {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+			fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+		if errno == 0 && ws.Col > 0 {
+			return ws.Row, ws.Col, true
+		}
+	}
+	return 0, 0, false
 }
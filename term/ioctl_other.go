@@ -0,0 +1,10 @@
+// +build aix js nacl plan9 windows android solaris
+
+package term
+
+// winsize cannot be detected on these systems so WinSize (or the COLUMNS
+// and LINES environment variables) must be used instead.
+func winsize() (row, col uint16, ok bool) { return 0, 0, false }
+
+// watchWinSize does nothing since there is no resize signal to watch.
+func watchWinSize() {}
@@ -0,0 +1,194 @@
+package term
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// KeyCode identifies the special (non-printable) keys decoded by
+// KeyReader. Printable keys have the KeyRune code.
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyDelete
+	KeyInsert
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyPaste
+	KeyUnknown
+)
+
+// Key is a single key press (or bracketed paste) decoded from terminal
+// input by KeyReader. Ctrl combinations have Ctrl set with the
+// lowercase letter as the Rune (Ctrl-A is Rune 'a'). Alt (Meta)
+// combinations have Alt set. Paste contains the full text of
+// a bracketed paste (KeyPaste).
+type Key struct {
+	Code  KeyCode
+	Rune  rune
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Paste string
+}
+
+// IsCtrl returns true if the key is the Ctrl combination for the given
+// (lowercase) letter.
+func (k Key) IsCtrl(r rune) bool {
+	return k.Ctrl && k.Code == KeyRune && k.Rune == r
+}
+
+// KeyReader decodes key presses from terminal input (usually in raw
+// mode, see MakeRaw) including the escape sequences for arrows, Home,
+// End, Ctrl and Alt combinations and bracketed paste. The embedded
+// bufio.Reader can still be used to read plain lines when not
+// interactive.
+type KeyReader struct {
+	*bufio.Reader
+}
+
+// NewKeyReader returns a new KeyReader reading from r.
+func NewKeyReader(r io.Reader) *KeyReader {
+	return &KeyReader{bufio.NewReader(r)}
+}
+
+// ReadKey blocks until the next complete key press has been read and
+// decoded. An escape not immediately followed by more buffered input is
+// taken to be the Escape key itself.
+func (k *KeyReader) ReadKey() (Key, error) {
+	r, _, err := k.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	switch {
+	case r == '\r' || r == '\n':
+		return Key{Code: KeyEnter}, nil
+	case r == '\t':
+		return Key{Code: KeyTab}, nil
+	case r == 0x7f || r == 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case r == 0x1b:
+		return k.readEscape()
+	case r > 0 && r < 27:
+		return Key{Code: KeyRune, Rune: 'a' + r - 1, Ctrl: true}, nil
+	}
+	return Key{Code: KeyRune, Rune: r}, nil
+}
+
+func (k *KeyReader) readEscape() (Key, error) {
+	if k.Buffered() == 0 {
+		return Key{Code: KeyEscape}, nil
+	}
+	r, _, err := k.ReadRune()
+	if err != nil {
+		return Key{Code: KeyEscape}, nil
+	}
+	switch r {
+	case '[':
+		return k.readCSI()
+	case 'O':
+		f, _, err := k.ReadRune()
+		if err != nil {
+			return Key{Code: KeyUnknown}, err
+		}
+		return Key{Code: final(f)}, nil
+	}
+	k.UnreadRune()
+	key, err := k.ReadKey()
+	key.Alt = true
+	return key, err
+}
+
+func (k *KeyReader) readCSI() (Key, error) {
+	var params []rune
+	var f rune
+	for {
+		r, _, err := k.ReadRune()
+		if err != nil {
+			return Key{Code: KeyUnknown}, err
+		}
+		if r >= 0x40 && r <= 0x7e {
+			f = r
+			break
+		}
+		params = append(params, r)
+	}
+	p := strings.Split(string(params), ";")
+	key := Key{Code: final(f)}
+	if f == '~' {
+		switch p[0] {
+		case "1", "7":
+			key.Code = KeyHome
+		case "4", "8":
+			key.Code = KeyEnd
+		case "2":
+			key.Code = KeyInsert
+		case "3":
+			key.Code = KeyDelete
+		case "5":
+			key.Code = KeyPageUp
+		case "6":
+			key.Code = KeyPageDown
+		case "200":
+			return k.readPaste()
+		}
+	}
+	if len(p) > 1 {
+		switch p[1] {
+		case "2":
+			key.Shift = true
+		case "3":
+			key.Alt = true
+		case "5":
+			key.Ctrl = true
+		}
+	}
+	return key, nil
+}
+
+func final(f rune) KeyCode {
+	switch f {
+	case 'A':
+		return KeyUp
+	case 'B':
+		return KeyDown
+	case 'C':
+		return KeyRight
+	case 'D':
+		return KeyLeft
+	case 'H':
+		return KeyHome
+	case 'F':
+		return KeyEnd
+	}
+	return KeyUnknown
+}
+
+const pasteEnd = "\033[201~"
+
+func (k *KeyReader) readPaste() (Key, error) {
+	var buf strings.Builder
+	for {
+		r, _, err := k.ReadRune()
+		if err != nil {
+			return Key{Code: KeyPaste, Paste: buf.String()}, err
+		}
+		buf.WriteRune(r)
+		if strings.HasSuffix(buf.String(), pasteEnd) {
+			return Key{Code: KeyPaste,
+				Paste: strings.TrimSuffix(buf.String(), pasteEnd)}, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package term
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	in := "a\r\t\x7f\x01\x1b[A\x1b[B\x1b[C\x1b[D\x1b[H\x1b[F\x1bOH" +
+		"\x1b[3~\x1b[1;5C\x1bb\x1b[200~pasted\ntext\x1b[201~\x1b"
+	want := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyEnter},
+		{Code: KeyTab},
+		{Code: KeyBackspace},
+		{Code: KeyRune, Rune: 'a', Ctrl: true},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyRight},
+		{Code: KeyLeft},
+		{Code: KeyHome},
+		{Code: KeyEnd},
+		{Code: KeyHome},
+		{Code: KeyDelete},
+		{Code: KeyRight, Ctrl: true},
+		{Code: KeyRune, Rune: 'b', Alt: true},
+		{Code: KeyPaste, Paste: "pasted\ntext"},
+		{Code: KeyEscape},
+	}
+	k := NewKeyReader(strings.NewReader(in))
+	for i, w := range want {
+		got, err := k.ReadKey()
+		if err != nil {
+			t.Fatalf("%v: %v", i, err)
+		}
+		if got != w {
+			t.Errorf("%v: want %+v got %+v", i, w, got)
+		}
+	}
+	if _, err := k.ReadKey(); err == nil {
+		t.Error("expected EOF")
+	}
+}
@@ -0,0 +1,416 @@
+package term
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwxrob/cmdbox/term/esc"
+)
+
+// ProgressInterval is how often a plain progress line is written for
+// a Bar or Spinner when their output is not an interactive terminal
+// (where they are redrawn in place instead).
+var ProgressInterval = 5 * time.Second
+
+// redrawInterval limits how often a Bar is redrawn in place
+const redrawInterval = 100 * time.Millisecond
+
+// isTerminalWriter returns true if w is a file connected to a terminal
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && IsTerminalFd(int(f.Fd()))
+}
+
+// Bar is a progress bar for work with a known Total (items or bytes)
+// showing percent complete, rate, and estimated time remaining. Use
+// Add or Set to update progress (safe for concurrency) and Finish when
+// done. When Out is an interactive terminal the bar is redrawn in place
+// to fit the width of the terminal (see Width). Otherwise, a plain line
+// is written every ProgressInterval and when finished. A Total of zero
+// or less shows only the amount done and rate. Also see Reader, Writer,
+// and Bars for more than one at a time.
+type Bar struct {
+	Label string
+	Total int64
+	Bytes bool      // show amounts as bytes (kB, MB, ...)
+	Out   io.Writer // default os.Stderr
+
+	mu      sync.Mutex
+	current int64
+	start   time.Time
+	drawn   time.Time
+	logged  time.Time
+	done    bool
+	written bool // final plain line
+	group   *Bars
+}
+
+// NewBar returns a new Bar with the label and total writing to
+// standard error. Progress starts being measured from this moment.
+func NewBar(label string, total int64) *Bar {
+	return &Bar{Label: label, Total: total, Out: os.Stderr,
+		start: time.Now(), logged: time.Now()}
+}
+
+// Add adds n to the current progress and updates the output.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	b.current += n
+	b.mu.Unlock()
+	b.update(false)
+}
+
+// Set sets the current progress to n and updates the output.
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	b.current = n
+	b.mu.Unlock()
+	b.update(false)
+}
+
+// Current returns the current progress.
+func (b *Bar) Current() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current
+}
+
+// Finish marks the Bar as done and draws (or writes) it a final time.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	b.done = true
+	b.mu.Unlock()
+	b.update(true)
+}
+
+func (b *Bar) out() io.Writer {
+	if b.Out == nil {
+		return os.Stderr
+	}
+	return b.Out
+}
+
+func (b *Bar) update(final bool) {
+	if b.group != nil {
+		b.group.update(final)
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.start.IsZero() {
+		b.start, b.logged = now, now
+	}
+	if !isTerminalWriter(b.out()) {
+		b.log(now)
+		return
+	}
+	if !final && now.Sub(b.drawn) < redrawInterval {
+		return
+	}
+	b.drawn = now
+	line := "\r" + b.line(Width()) + esc.ClearToEnd
+	if final {
+		line += "\n"
+	}
+	fmt.Fprint(b.out(), line)
+}
+
+// log writes a plain line if ProgressInterval has passed (or once when
+// done), must be called while locked
+func (b *Bar) log(now time.Time) {
+	if b.written || (!b.done && now.Sub(b.logged) < ProgressInterval) {
+		return
+	}
+	b.logged = now
+	b.written = b.done
+	fmt.Fprintln(b.out(), b.plain())
+}
+
+func (b *Bar) amount(n int64) string {
+	if b.Bytes {
+		return HumanBytes(n)
+	}
+	return fmt.Sprint(n)
+}
+
+// stats returns the amount done, the rate, and the ETA (if known)
+func (b *Bar) stats() (string, string, string) {
+	elapsed := time.Since(b.start).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(b.current) / elapsed
+	}
+	done := b.amount(b.current)
+	if b.Total > 0 {
+		done += "/" + b.amount(b.Total)
+	}
+	rates := b.amount(int64(rate+0.5)) + "/s"
+	eta := ""
+	if b.done {
+		eta = Duration(time.Since(b.start))
+	} else if b.Total > 0 && rate > 0 && b.current < b.Total {
+		eta = "ETA " + Duration(time.Duration(float64(b.Total-b.current)/rate*float64(time.Second)))
+	}
+	return done, rates, eta
+}
+
+func (b *Bar) percent() int {
+	if b.Total <= 0 {
+		return 0
+	}
+	p := int(b.current * 100 / b.Total)
+	if p > 100 {
+		p = 100
+	}
+	return p
+}
+
+// plain returns the line written when not interactive
+func (b *Bar) plain() string {
+	done, rate, eta := b.stats()
+	out := b.Label + ": "
+	if b.Total > 0 {
+		out += fmt.Sprintf("%v%% ", b.percent())
+	}
+	out += done + " " + rate
+	if b.done {
+		return out + " done in " + eta
+	}
+	if eta != "" {
+		out += " " + eta
+	}
+	return out
+}
+
+// line returns the bar drawn to fit within width
+func (b *Bar) line(width int) string {
+	done, rate, eta := b.stats()
+	info := " " + done + " " + rate
+	if eta != "" {
+		info += " " + eta
+	}
+	label := b.Label
+	if label != "" {
+		label += " "
+	}
+	if b.Total <= 0 {
+		return label + strings.TrimSpace(info)
+	}
+	info = fmt.Sprintf(" %3v%%", b.percent()) + info
+	size := width - len([]rune(label)) - len(info) - 3
+	if size < 10 {
+		return label + strings.TrimSpace(info)
+	}
+	full := size * b.percent() / 100
+	bar := strings.Repeat("=", full)
+	if full < size {
+		bar += ">" + strings.Repeat(" ", size-full-1)
+	}
+	return label + "[" + bar + "]" + info
+}
+
+// Reader returns an io.Reader that adds everything read from r to the
+// progress of the Bar (which is set to show Bytes).
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	b.Bytes = true
+	return &progressReader{r, b}
+}
+
+// Writer returns an io.Writer that adds everything written to w to
+// the progress of the Bar (which is set to show Bytes).
+func (b *Bar) Writer(w io.Writer) io.Writer {
+	b.Bytes = true
+	return &progressWriter{w, b}
+}
+
+type progressReader struct {
+	r io.Reader
+	b *Bar
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.b.Add(int64(n))
+	return n, err
+}
+
+type progressWriter struct {
+	w io.Writer
+	b *Bar
+}
+
+func (p *progressWriter) Write(buf []byte) (int, error) {
+	n, err := p.w.Write(buf)
+	p.b.Add(int64(n))
+	return n, err
+}
+
+// Bars draws more than one Bar at a time (one per line) for concurrent
+// work. Create each Bar with Add. Every Bar in the group may be updated
+// from its own goroutine. When not interactive each Bar writes its own
+// plain lines.
+type Bars struct {
+	Out io.Writer // default os.Stderr
+
+	mu    sync.Mutex
+	bars  []*Bar
+	lines int
+	drawn time.Time
+}
+
+// NewBars returns a new group of Bars writing to standard error.
+func NewBars() *Bars { return &Bars{Out: os.Stderr} }
+
+// Add creates a new Bar in the group and returns it.
+func (g *Bars) Add(label string, total int64) *Bar {
+	b := NewBar(label, total)
+	b.Out = g.Out
+	b.group = g
+	g.mu.Lock()
+	g.bars = append(g.bars, b)
+	g.mu.Unlock()
+	return b
+}
+
+func (g *Bars) update(final bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !isTerminalWriter(g.Out) {
+		for _, b := range g.bars {
+			b.mu.Lock()
+			b.log(time.Now())
+			b.mu.Unlock()
+		}
+		return
+	}
+	now := time.Now()
+	if !final && now.Sub(g.drawn) < redrawInterval {
+		return
+	}
+	g.drawn = now
+	buf := ""
+	if g.lines > 0 {
+		buf += "\r" + esc.Up(g.lines)
+	}
+	for _, b := range g.bars {
+		b.mu.Lock()
+		buf += "\r" + b.line(Width()) + esc.ClearToEnd + "\n"
+		b.mu.Unlock()
+	}
+	g.lines = len(g.bars)
+	fmt.Fprint(g.Out, buf)
+}
+
+// Spinner shows that something is happening when how long it will take
+// is unknown. When Out is an interactive terminal the Label is shown
+// after a spinning frame redrawn in place every Interval. Otherwise, the
+// Label is written once when started, again every ProgressInterval, and
+// with "done" when stopped.
+type Spinner struct {
+	Label    string
+	Frames   []string
+	Interval time.Duration
+	Out      io.Writer // default os.Stderr
+
+	mu    sync.Mutex
+	stop  chan struct{}
+	ended chan struct{}
+	start time.Time
+}
+
+// NewSpinner returns a new Spinner with the label using the default
+// frames, interval, and standard error.
+func NewSpinner(label string) *Spinner {
+	return &Spinner{
+		Label:    label,
+		Frames:   []string{"|", "/", "-", "\\"},
+		Interval: redrawInterval,
+		Out:      os.Stderr,
+	}
+}
+
+// Start starts the spinner in its own goroutine.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	if s.Out == nil {
+		s.Out = os.Stderr
+	}
+	s.stop = make(chan struct{})
+	s.ended = make(chan struct{})
+	s.start = time.Now()
+	interval := s.Interval
+	interactive := isTerminalWriter(s.Out)
+	if !interactive {
+		interval = ProgressInterval
+		fmt.Fprintln(s.Out, s.Label)
+	}
+	go func() {
+		defer close(s.ended)
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for n := 0; ; n++ {
+			if interactive && len(s.Frames) > 0 {
+				fmt.Fprint(s.Out, "\r"+s.Frames[n%len(s.Frames)]+" "+
+					s.Label+esc.ClearToEnd)
+			}
+			select {
+			case <-s.stop:
+				return
+			case <-tick.C:
+				if !interactive {
+					fmt.Fprintf(s.Out, "%v (%v)\n", s.Label,
+						Duration(time.Since(s.start)))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the spinner and writes the Label a final time followed by
+// done and how long it took.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.ended
+	s.stop = nil
+	line := fmt.Sprintf("%v done in %v", s.Label, Duration(time.Since(s.start)))
+	if isTerminalWriter(s.Out) {
+		line = "\r" + line + esc.ClearToEnd
+	}
+	fmt.Fprintln(s.Out, line)
+}
+
+// HumanBytes returns the number of bytes in the largest unit (B, kB,
+// MB, GB, TB) keeping a single decimal place.
+func HumanBytes(n int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	f := float64(n)
+	u := 0
+	for f >= 1000 && u < len(units)-1 {
+		f /= 1000
+		u++
+	}
+	if u == 0 {
+		return fmt.Sprintf("%v%v", n, units[0])
+	}
+	return fmt.Sprintf("%.1f%v", f, units[u])
+}
+
+// Duration returns the duration rounded to the second in the short
+// form used by progress lines (1h2m3s, 4m5s, 6s).
+func Duration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,103 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHumanBytes(t *testing.T) {
+	tests := map[int64]string{
+		0: "0B", 999: "999B", 1000: "1.0kB", 1500000: "1.5MB",
+		3200000000: "3.2GB", 5e15: "5000.0TB",
+	}
+	for n, want := range tests {
+		if got := HumanBytes(n); got != want {
+			t.Errorf("%v: want %v got %v", n, want, got)
+		}
+	}
+}
+
+func TestBar_line(t *testing.T) {
+	b := NewBar("fetch", 200)
+	b.start = time.Now().Add(-2 * time.Second)
+	b.current = 100
+	got := b.line(50)
+	want := "fetch [========>       ]  50% 100/200 50/s ETA 2s" // leaves last column
+	if got != want {
+		t.Errorf("\nwant %q\ngot  %q", want, got)
+	}
+	if got := b.line(20); got != "fetch 50% 100/200 50/s ETA 2s" {
+		t.Errorf("too narrow for bar: %q", got)
+	}
+	b.Total = 0
+	if got := b.line(50); got != "fetch 100 50/s" {
+		t.Errorf("unknown total: %q", got)
+	}
+}
+
+func TestBar_plain(t *testing.T) {
+	out := new(bytes.Buffer)
+	b := NewBar("copy", 10)
+	b.Out = out
+	r := b.Reader(strings.NewReader("0123456789"))
+	io.Copy(ioutil.Discard, r) // faster than ProgressInterval
+	if out.Len() != 0 {
+		t.Errorf("should not log before interval: %q", out.String())
+	}
+	b.Finish()
+	b.Finish()
+	if !strings.HasPrefix(out.String(), "copy: 100% 10B/10B ") ||
+		!strings.Contains(out.String(), " done in ") ||
+		strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("unexpected: %q", out.String())
+	}
+}
+
+func TestBar_interval(t *testing.T) {
+	defer func(i time.Duration) { ProgressInterval = i }(ProgressInterval)
+	ProgressInterval = 0
+	out := new(bytes.Buffer)
+	b := NewBar("items", 4)
+	b.Out = out
+	b.Add(1)
+	b.Add(1)
+	if strings.Count(out.String(), "\n") != 2 {
+		t.Errorf("want two lines: %q", out.String())
+	}
+}
+
+func TestBars_plain(t *testing.T) {
+	out := new(bytes.Buffer)
+	g := NewBars()
+	g.Out = out
+	one := g.Add("one", 2)
+	two := g.Add("two", 2)
+	w := two.Writer(ioutil.Discard)
+	one.Add(2)
+	w.Write([]byte("hi"))
+	one.Finish()
+	two.Finish()
+	two.Finish()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "one: 100% 2/2") ||
+		!strings.HasPrefix(lines[1], "two: 100% 2B/2B") {
+		t.Errorf("unexpected: %q", out.String())
+	}
+}
+
+func TestSpinner_plain(t *testing.T) {
+	out := new(bytes.Buffer)
+	s := NewSpinner("thinking")
+	s.Out = out
+	s.Start()
+	s.Stop()
+	s.Stop()
+	if !strings.HasPrefix(out.String(), "thinking\nthinking done in ") ||
+		strings.Count(out.String(), "\n") != 2 {
+		t.Errorf("unexpected: %q", out.String())
+	}
+}
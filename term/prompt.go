@@ -0,0 +1,374 @@
+package term
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/rwxrob/cmdbox/term/esc"
+)
+
+// PromptOut is where all prompts are written. Standard error is used by
+// default so that prompts never end up mixed into output that is being
+// piped or redirected.
+var PromptOut io.Writer = os.Stderr
+
+// ChooseMax is the maximum number of options shown at once by Choose
+// and ChooseMany on an interactive terminal.
+var ChooseMax = 10
+
+var input = struct {
+	sync.Mutex
+	f    *os.File
+	keys *KeyReader
+}{}
+
+// promptIn returns the KeyReader shared by all prompts so that no
+// buffered input is lost between them, replacing it if os.Stdin has
+// changed (when mocked, for example)
+func promptIn() *KeyReader {
+	input.Lock()
+	defer input.Unlock()
+	if input.keys == nil || input.f != os.Stdin {
+		input.f = os.Stdin
+		input.keys = NewKeyReader(os.Stdin)
+	}
+	return input.keys
+}
+
+// StdinEditor returns a LineEditor for standard input that reads
+// through the same buffered reader as all the prompts (see Input) so
+// that nothing typed ahead (or piped) is lost when switching between
+// them.
+func StdinEditor() *LineEditor {
+	return &LineEditor{In: os.Stdin, keys: promptIn()}
+}
+
+// readLine reads a line with a LineEditor (which only prompts and edits
+// when interactive) printing the prompt first for plain lines
+func readLine(prompt string) (string, error) {
+	e := &LineEditor{In: os.Stdin, Out: PromptOut, keys: promptIn()}
+	if !IsInputTerminal() {
+		fmt.Fprint(PromptOut, prompt)
+	}
+	return e.ReadLine(prompt)
+}
+
+// Input prompts for a line of text (with full line editing when
+// interactive, see LineEditor) and returns it once valid returns nil
+// (valid may be nil to accept anything). The validation error is shown
+// and the prompt repeated on an interactive terminal. Otherwise, lines
+// are read plainly from standard input and the validation error is
+// returned.
+func Input(prompt string, valid func(in string) error) (string, error) {
+	for {
+		line, err := readLine(prompt)
+		if err != nil {
+			return line, err
+		}
+		if valid == nil {
+			return line, nil
+		}
+		if err := valid(line); err != nil {
+			if !IsInputTerminal() {
+				return line, err
+			}
+			fmt.Fprintln(PromptOut, err)
+			continue
+		}
+		return line, nil
+	}
+}
+
+// NotEmpty is a validator for Input requiring something other than
+// white space.
+func NotEmpty(in string) error {
+	if strings.TrimSpace(in) == "" {
+		return errors.New("cannot be empty")
+	}
+	return nil
+}
+
+// Confirm prompts for a yes or no answer (y, yes, n, no, in any case)
+// returning def if nothing is entered. The prompt is followed by [Y/n]
+// or [y/N] to indicate the default.
+func Confirm(prompt string, def bool) (bool, error) {
+	hint := " [y/N] "
+	if def {
+		hint = " [Y/n] "
+	}
+	answer := def
+	_, err := Input(prompt+hint, func(in string) error {
+		switch strings.ToLower(strings.TrimSpace(in)) {
+		case "":
+			answer = def
+		case "y", "yes":
+			answer = true
+		case "n", "no":
+			answer = false
+		default:
+			return errors.New("please answer yes or no")
+		}
+		return nil
+	})
+	return answer, err
+}
+
+// Secret prompts for a line of text without echoing what is typed
+// (passwords, tokens, etc.) when standard input is an interactive
+// terminal. The terminal is put into raw mode (see MakeRaw) so that
+// Ctrl-C returns ErrInterrupt (rather than killing the process with
+// echo still off) and Ctrl-D on an empty line returns io.EOF.
+// Backspace and Ctrl-U are the only editing keys. Otherwise, the line
+// is read plainly.
+func Secret(prompt string) (string, error) {
+	if !IsInputTerminal() {
+		return readLine(prompt)
+	}
+	fmt.Fprint(PromptOut, prompt)
+	state, err := MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer Restore(int(os.Stdin.Fd()), state)
+	return secret(promptIn())
+}
+
+// secret reads the keys of a secret line (see Secret) without echoing
+// anything but the final line return
+func secret(keys *KeyReader) (string, error) {
+	buf := []rune{}
+	for {
+		k, err := keys.ReadKey()
+		if err != nil {
+			fmt.Fprint(PromptOut, "\r\n")
+			if len(buf) > 0 && err == io.EOF {
+				return string(buf), nil
+			}
+			return "", err
+		}
+		switch {
+		case k.Code == KeyEnter:
+			fmt.Fprint(PromptOut, "\r\n")
+			return string(buf), nil
+		case k.IsCtrl('c'):
+			fmt.Fprint(PromptOut, "^C\r\n")
+			return "", ErrInterrupt
+		case k.IsCtrl('d') && len(buf) == 0:
+			fmt.Fprint(PromptOut, "\r\n")
+			return "", io.EOF
+		case k.Code == KeyBackspace:
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+			}
+		case k.IsCtrl('u'):
+			buf = buf[:0]
+		case k.Code == KeyPaste:
+			buf = append(buf, []rune(k.Paste)...)
+		case k.Code == KeyRune && !k.Ctrl && !k.Alt:
+			buf = append(buf, k.Rune)
+		}
+	}
+}
+
+// Choose prompts for a single choice from the options returning its
+// index. On an interactive terminal the options are shown as a list to
+// move through with the arrow keys (or Ctrl-P and Ctrl-N) and typing
+// filters the list to those options containing what was typed. Enter
+// chooses and Escape or Ctrl-C return ErrInterrupt. Otherwise, the
+// numbered options are printed and either the number or the exact
+// option is read from a plain line.
+func Choose(prompt string, options []string) (int, error) {
+	chosen, err := choose(prompt, options, false)
+	if err != nil || len(chosen) == 0 {
+		return -1, err
+	}
+	return chosen[0], nil
+}
+
+// ChooseMany is the same as Choose but allows more than one choice
+// returning the sorted indexes of all those chosen. On an interactive
+// terminal Space or Tab toggles the option under the cursor and Enter
+// finishes (choosing the option under the cursor if nothing else was
+// chosen). Otherwise, multiple numbers or options may be entered on the
+// line separated by commas or spaces.
+func ChooseMany(prompt string, options []string) ([]int, error) {
+	return choose(prompt, options, true)
+}
+
+func choose(prompt string, options []string, multi bool) ([]int, error) {
+	if len(options) == 0 {
+		return nil, errors.New("nothing to choose from")
+	}
+	if !IsInputTerminal() {
+		for i, o := range options {
+			fmt.Fprintf(PromptOut, "%3v) %v\n", i+1, o)
+		}
+		line, err := readLine(prompt)
+		if err != nil {
+			return nil, err
+		}
+		return parseChoices(line, options, multi)
+	}
+	state, err := MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	defer Restore(int(os.Stdin.Fd()), state)
+	c := &chooser{options: options, multi: multi, out: PromptOut}
+	return c.run(promptIn(), prompt)
+}
+
+func parseChoices(line string, options []string, multi bool) ([]int, error) {
+	var words []string
+	if multi {
+		words = strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	} else {
+		words = []string{strings.TrimSpace(line)}
+	}
+	chosen := []int{}
+WORDS:
+	for _, w := range words {
+		if n, err := strconv.Atoi(w); err == nil && n > 0 && n <= len(options) {
+			chosen = append(chosen, n-1)
+			continue
+		}
+		for i, o := range options {
+			if o == w {
+				chosen = append(chosen, i)
+				continue WORDS
+			}
+		}
+		return nil, fmt.Errorf("invalid choice: %v", w)
+	}
+	if len(chosen) == 0 {
+		return nil, errors.New("nothing chosen")
+	}
+	sort.Ints(chosen)
+	return chosen, nil
+}
+
+// chooser is the interactive list for Choose and ChooseMany
+type chooser struct {
+	options []string
+	multi   bool
+	out     io.Writer
+	filter  []rune
+	cursor  int
+	chosen  map[int]bool
+}
+
+func (c *chooser) visible() []int {
+	f := strings.ToLower(string(c.filter))
+	v := []int{}
+	for i, o := range c.options {
+		if strings.Contains(strings.ToLower(o), f) {
+			v = append(v, i)
+		}
+	}
+	return v
+}
+
+func (c *chooser) run(keys *KeyReader, prompt string) ([]int, error) {
+	c.chosen = map[int]bool{}
+	for {
+		vis := c.visible()
+		if c.cursor >= len(vis) {
+			c.cursor = len(vis) - 1
+		}
+		if c.cursor < 0 {
+			c.cursor = 0
+		}
+		c.draw(prompt, vis)
+		k, err := keys.ReadKey()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case k.IsCtrl('c') || k.Code == KeyEscape:
+			c.finish(prompt, nil)
+			return nil, ErrInterrupt
+		case k.Code == KeyUp || k.IsCtrl('p'):
+			c.cursor--
+		case k.Code == KeyDown || k.IsCtrl('n'):
+			c.cursor++
+		case k.Code == KeyBackspace:
+			if len(c.filter) > 0 {
+				c.filter = c.filter[:len(c.filter)-1]
+			}
+		case c.multi && (k.Code == KeyTab || (k.Code == KeyRune && k.Rune == ' ')):
+			if len(vis) > 0 {
+				i := vis[c.cursor]
+				c.chosen[i] = !c.chosen[i]
+			}
+		case k.Code == KeyEnter:
+			chosen := []int{}
+			for i, yes := range c.chosen {
+				if yes {
+					chosen = append(chosen, i)
+				}
+			}
+			if len(chosen) == 0 && len(vis) > 0 {
+				chosen = append(chosen, vis[c.cursor])
+			}
+			if len(chosen) == 0 {
+				continue
+			}
+			sort.Ints(chosen)
+			c.finish(prompt, chosen)
+			return chosen, nil
+		case k.Code == KeyRune && !k.Ctrl && !k.Alt && unicode.IsPrint(k.Rune):
+			c.filter = append(c.filter, k.Rune)
+			c.cursor = 0
+		}
+	}
+}
+
+// draw always leaves the cursor at the end of the prompt line
+func (c *chooser) draw(prompt string, vis []int) {
+	buf := "\r" + esc.ClearBelow + prompt + string(c.filter)
+	start := 0
+	if c.cursor >= ChooseMax {
+		start = c.cursor - ChooseMax + 1
+	}
+	n := start
+	for ; n < len(vis) && n < start+ChooseMax; n++ {
+		line := "  "
+		if n == c.cursor {
+			line = "> "
+		}
+		if c.multi {
+			if c.chosen[vis[n]] {
+				line += "[x] "
+			} else {
+				line += "[ ] "
+			}
+		}
+		buf += "\r\n" + line + c.options[vis[n]]
+	}
+	if n > start {
+		buf += "\r" + esc.Up(n-start)
+		// some terminals take a zero count to mean one column
+		if col := len([]rune(prompt)) + len(c.filter); col > 0 {
+			buf += esc.Right(col)
+		}
+	}
+	fmt.Fprint(c.out, buf)
+}
+
+// finish clears the list leaving the prompt and what was chosen
+func (c *chooser) finish(prompt string, chosen []int) {
+	names := []string{}
+	for _, i := range chosen {
+		names = append(names, c.options[i])
+	}
+	fmt.Fprint(c.out, "\r"+esc.ClearBelow+prompt+strings.Join(names, ", ")+"\r\n")
+}
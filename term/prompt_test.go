@@ -0,0 +1,139 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rwxrob/cmdbox/term/esc"
+)
+
+// mockPrompt replaces the shared prompt input with the string passed
+// and captures all prompt output
+func mockPrompt(in string) *bytes.Buffer {
+	input.f = os.Stdin
+	input.keys = NewKeyReader(strings.NewReader(in))
+	out := new(bytes.Buffer)
+	PromptOut = out
+	return out
+}
+
+func TestInput(t *testing.T) {
+	defer func() { PromptOut = os.Stderr }()
+	out := mockPrompt("rob\n\n")
+	got, err := Input("name: ", NotEmpty)
+	if err != nil || got != "rob" {
+		t.Errorf("want rob got %q (%v)", got, err)
+	}
+	if out.String() != "name: " {
+		t.Errorf("unexpected prompt: %q", out.String())
+	}
+	if _, err := Input("name: ", NotEmpty); err == nil {
+		t.Error("expected validation error")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	defer func() { PromptOut = os.Stderr }()
+	mockPrompt("y\nNo\n\n\nmaybe\n")
+	for i, want := range []bool{true, false, true, false} {
+		got, err := Confirm("sure?", i != 3)
+		if err != nil || got != want {
+			t.Errorf("%v: want %v got %v (%v)", i, want, got, err)
+		}
+	}
+	if _, err := Confirm("sure?", true); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestSecret_plain(t *testing.T) {
+	defer func() { PromptOut = os.Stderr }()
+	mockPrompt("s3cr3t\n")
+	got, err := Secret("password: ")
+	if err != nil || got != "s3cr3t" {
+		t.Errorf("want s3cr3t got %q (%v)", got, err)
+	}
+}
+
+func TestSecret_keys(t *testing.T) {
+	var out bytes.Buffer
+	PromptOut = &out
+	defer func() { PromptOut = os.Stderr }()
+	got, err := secret(NewKeyReader(strings.NewReader("s3x\x7fcr3t\r")))
+	if err != nil || got != "s3cr3t" {
+		t.Errorf("want s3cr3t got %q (%v)", got, err)
+	}
+	if strings.Contains(out.String(), "s3") {
+		t.Errorf("secret echoed: %q", out.String())
+	}
+	if _, err := secret(NewKeyReader(strings.NewReader("abc\x03"))); err != ErrInterrupt {
+		t.Errorf("want ErrInterrupt got %v", err)
+	}
+}
+
+func TestChoose_plain(t *testing.T) {
+	defer func() { PromptOut = os.Stderr }()
+	out := mockPrompt("2\nblue\npurple\n")
+	colors := []string{"red", "green", "blue"}
+	for _, want := range []int{1, 2} {
+		got, err := Choose("color: ", colors)
+		if err != nil || got != want {
+			t.Errorf("want %v got %v (%v)", want, got, err)
+		}
+	}
+	if _, err := Choose("color: ", colors); err == nil {
+		t.Error("expected invalid choice")
+	}
+	if !strings.HasPrefix(out.String(), "  1) red\n  2) green\n  3) blue\ncolor: ") {
+		t.Errorf("unexpected prompt: %q", out.String())
+	}
+}
+
+func TestChooseMany_plain(t *testing.T) {
+	defer func() { PromptOut = os.Stderr }()
+	mockPrompt("3, red 2\n")
+	got, err := ChooseMany("colors: ", []string{"red", "green", "blue"})
+	if err != nil || fmt.Sprint(got) != "[0 1 2]" {
+		t.Errorf("want [0 1 2] got %v (%v)", got, err)
+	}
+}
+
+func TestChooser_run(t *testing.T) {
+	colors := []string{"red", "green", "blue", "black"}
+	tests := []struct {
+		in    string
+		multi bool
+		want  string
+	}{
+		{"\r", false, "[0]"},
+		{"\x1b[B\x1b[B\r", false, "[2]"},
+		{"bl\x1b[B\r", false, "[3]"},
+		{"bl\x7f\x7fgr\r", false, "[1]"},
+		{" \x1b[B\x1b[B \r", true, "[0 2]"},
+		{"bl\t\x1b[B\t\r", true, "[2 3]"},
+		{"\x1b[B\r", true, "[1]"},
+	}
+	for _, test := range tests {
+		c := &chooser{options: colors, multi: test.multi, out: new(bytes.Buffer)}
+		got, err := c.run(NewKeyReader(strings.NewReader(test.in)), "? ")
+		if err != nil || fmt.Sprint(got) != test.want {
+			t.Errorf("%q: want %v got %v (%v)", test.in, test.want, got, err)
+		}
+	}
+	c := &chooser{options: colors, out: new(bytes.Buffer)}
+	if _, err := c.run(NewKeyReader(strings.NewReader("\x03")), "? "); err != ErrInterrupt {
+		t.Errorf("want ErrInterrupt got %v", err)
+	}
+}
+
+func TestChooser_draw_noPrompt(t *testing.T) {
+	out := new(bytes.Buffer)
+	c := &chooser{options: []string{"red", "green"}, out: out}
+	c.draw("", c.visible())
+	if strings.Contains(out.String(), esc.Right(0)) {
+		t.Errorf("zero move right drawn: %q", out.String())
+	}
+}
@@ -0,0 +1,90 @@
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// State contains the terminal settings (termios) of a file descriptor
+// as they were before changing them with MakeRaw or NoEcho so that they
+// can be put back with Restore.
+type State struct {
+	termios syscall.Termios
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	t := new(syscall.Termios)
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd),
+		uintptr(ioctlGetTermios), uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return t, nil
+}
+
+func setTermios(fd int, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd),
+		uintptr(ioctlSetTermios), uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// IsTerminalFd returns true if the file descriptor is a terminal.
+func IsTerminalFd(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// MakeRaw puts the terminal connected to the file descriptor into raw
+// mode (no echo, no line buffering, no signals from Ctrl-C and such)
+// returning its previous State for Restore. Output processing is left
+// on so that normal line returns still work while in raw mode.
+func MakeRaw(fd int) (*State, error) {
+	t, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	old := &State{*t}
+	t.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK |
+		syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL |
+		syscall.IXON
+	t.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON |
+		syscall.ISIG | syscall.IEXTEN
+	t.Cflag &^= syscall.CSIZE | syscall.PARENB
+	t.Cflag |= syscall.CS8
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+	if err := setTermios(fd, t); err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+// NoEcho turns off echoing of input to the terminal connected to the
+// file descriptor (leaving line editing alone) returning its previous
+// State for Restore. Note that Ctrl-C still sends an interrupt signal
+// that kills the process (leaving echo off) unless the caller restores
+// the terminal on SIGINT (see Secret, which uses MakeRaw instead).
+func NoEcho(fd int) (*State, error) {
+	t, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	old := &State{*t}
+	t.Lflag &^= syscall.ECHO
+	t.Lflag |= syscall.ICANON | syscall.ISIG
+	if err := setTermios(fd, t); err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+// Restore puts the terminal connected to the file descriptor back into
+// the State returned from MakeRaw or NoEcho.
+func Restore(fd int, s *State) error {
+	return setTermios(fd, &s.termios)
+}
@@ -0,0 +1,24 @@
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package term
+
+import "errors"
+
+// ErrUnsupported is returned when terminal modes cannot be changed on
+// the current operating system.
+var ErrUnsupported = errors.New("terminal modes unsupported")
+
+// State contains the terminal settings to be put back with Restore.
+type State struct{}
+
+// IsTerminalFd always returns false on these systems.
+func IsTerminalFd(fd int) bool { return false }
+
+// MakeRaw is unsupported on these systems.
+func MakeRaw(fd int) (*State, error) { return nil, ErrUnsupported }
+
+// NoEcho is unsupported on these systems.
+func NoEcho(fd int) (*State, error) { return nil, ErrUnsupported }
+
+// Restore is unsupported on these systems.
+func Restore(fd int, s *State) error { return ErrUnsupported }
@@ -0,0 +1,293 @@
+package term
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/rwxrob/cmdbox/term/esc"
+)
+
+// ErrInterrupt is returned by LineEditor.ReadLine when Ctrl-C is pressed.
+var ErrInterrupt = errors.New("interrupt")
+
+// LineEditor is a minimal readline for interactive prompts supporting
+// cursor movement, kill and yank, history, and tab completion using
+// the common Emacs (Bash) key bindings:
+//
+//     Left, Ctrl-B         back one character
+//     Right, Ctrl-F        forward one character
+//     Home, Ctrl-A         beginning of line
+//     End, Ctrl-E          end of line
+//     Alt-B, Alt-F         back or forward one word
+//     Backspace, Ctrl-H    delete previous character
+//     Delete               delete character under cursor
+//     Ctrl-D               same as Delete (or io.EOF if line empty)
+//     Ctrl-K               kill to end of line
+//     Ctrl-U               kill to beginning of line
+//     Ctrl-W               kill previous word
+//     Ctrl-Y               yank last killed text
+//     Up, Ctrl-P           previous History line
+//     Down, Ctrl-N         next History line
+//     Tab                  complete (see Complete)
+//     Ctrl-L               clear screen
+//     Ctrl-C               return ErrInterrupt
+//     Enter                return line
+//
+// When In is not a terminal ReadLine reads plain lines instead (without
+// prompting or editing) so that the same code works when input is
+// piped or redirected. The zero value is ready to use with standard
+// input and output.
+type LineEditor struct {
+	In       io.Reader // default os.Stdin
+	Out      io.Writer // default os.Stdout
+	History  []string  // oldest first, appended with each line read
+	Complete func(line string) []string
+
+	keys   *KeyReader
+	buf    []rune
+	pos    int
+	killed []rune
+}
+
+// ReadLine prints the prompt and returns the line edited by the user
+// (without the line return) adding it to the History if not empty. The
+// terminal is put into raw mode (see MakeRaw) only for the duration of
+// the call. Returns io.EOF if Ctrl-D is pressed on an empty line (or
+// input ends) and ErrInterrupt on Ctrl-C.
+func (e *LineEditor) ReadLine(prompt string) (string, error) {
+	if e.In == nil {
+		e.In = os.Stdin
+	}
+	if e.Out == nil {
+		e.Out = os.Stdout
+	}
+	if e.keys == nil {
+		e.keys = NewKeyReader(e.In)
+	}
+	f, isfile := e.In.(*os.File)
+	if !isfile || !IsTerminalFd(int(f.Fd())) {
+		line, err := e.keys.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return line, err
+	}
+	state, err := MakeRaw(int(f.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer Restore(int(f.Fd()), state)
+	fmt.Fprint(e.Out, "\033[?2004h") // bracketed paste on
+	defer fmt.Fprint(e.Out, "\033[?2004l")
+	return e.edit(prompt)
+}
+
+// edit does the actual editing reading from keys and drawing to Out
+func (e *LineEditor) edit(prompt string) (string, error) {
+	e.buf = []rune{}
+	e.pos = 0
+	hist := len(e.History)
+	saved := []rune{}
+	e.draw(prompt)
+	for {
+		k, err := e.keys.ReadKey()
+		if err != nil {
+			if len(e.buf) > 0 && err == io.EOF {
+				return e.done(), nil
+			}
+			return "", err
+		}
+		switch {
+
+		case k.Code == KeyEnter:
+			return e.done(), nil
+
+		case k.IsCtrl('c'):
+			fmt.Fprint(e.Out, "^C\r\n")
+			return "", ErrInterrupt
+
+		case k.IsCtrl('d') && len(e.buf) == 0:
+			fmt.Fprint(e.Out, "\r\n")
+			return "", io.EOF
+
+		case k.Code == KeyLeft && (k.Ctrl || k.Alt), k.Alt && k.Rune == 'b':
+			e.pos = e.wordStart()
+
+		case k.Code == KeyRight && (k.Ctrl || k.Alt), k.Alt && k.Rune == 'f':
+			e.pos = e.wordEnd()
+
+		case k.Code == KeyLeft || k.IsCtrl('b'):
+			if e.pos > 0 {
+				e.pos--
+			}
+
+		case k.Code == KeyRight || k.IsCtrl('f'):
+			if e.pos < len(e.buf) {
+				e.pos++
+			}
+
+		case k.Code == KeyHome || k.IsCtrl('a'):
+			e.pos = 0
+
+		case k.Code == KeyEnd || k.IsCtrl('e'):
+			e.pos = len(e.buf)
+
+		case k.Code == KeyBackspace || k.IsCtrl('h'):
+			if e.pos > 0 {
+				e.buf = append(e.buf[:e.pos-1], e.buf[e.pos:]...)
+				e.pos--
+			}
+
+		case k.Code == KeyDelete || k.IsCtrl('d'):
+			if e.pos < len(e.buf) {
+				e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+			}
+
+		case k.IsCtrl('k'):
+			e.kill(e.pos, len(e.buf))
+
+		case k.IsCtrl('u'):
+			e.kill(0, e.pos)
+
+		case k.IsCtrl('w'):
+			e.kill(e.wordStart(), e.pos)
+
+		case k.IsCtrl('y'):
+			e.insert(e.killed...)
+
+		case k.Code == KeyUp || k.IsCtrl('p'):
+			if hist > 0 {
+				if hist == len(e.History) {
+					saved = e.buf
+				}
+				hist--
+				e.buf = []rune(e.History[hist])
+				e.pos = len(e.buf)
+			}
+
+		case k.Code == KeyDown || k.IsCtrl('n'):
+			if hist < len(e.History) {
+				hist++
+				if hist == len(e.History) {
+					e.buf = saved
+				} else {
+					e.buf = []rune(e.History[hist])
+				}
+				e.pos = len(e.buf)
+			}
+
+		case k.Code == KeyTab:
+			e.complete(prompt)
+
+		case k.IsCtrl('l'):
+			fmt.Fprint(e.Out, esc.ClearScreen)
+
+		case k.Code == KeyPaste:
+			e.insert([]rune(strings.ReplaceAll(k.Paste, "\n", " "))...)
+
+		case k.Code == KeyRune && !k.Ctrl && !k.Alt && unicode.IsPrint(k.Rune):
+			e.insert(k.Rune)
+
+		}
+		e.draw(prompt)
+	}
+}
+
+func (e *LineEditor) done() string {
+	fmt.Fprint(e.Out, "\r\n")
+	line := string(e.buf)
+	if strings.TrimSpace(line) != "" {
+		e.History = append(e.History, line)
+	}
+	return line
+}
+
+func (e *LineEditor) draw(prompt string) {
+	fmt.Fprint(e.Out, "\r"+prompt+string(e.buf)+esc.ClearToEnd)
+	if back := len(e.buf) - e.pos; back > 0 {
+		fmt.Fprint(e.Out, esc.Left(back))
+	}
+}
+
+func (e *LineEditor) insert(r ...rune) {
+	buf := make([]rune, 0, len(e.buf)+len(r))
+	buf = append(buf, e.buf[:e.pos]...)
+	buf = append(buf, r...)
+	e.buf = append(buf, e.buf[e.pos:]...)
+	e.pos += len(r)
+}
+
+func (e *LineEditor) kill(from, to int) {
+	if from >= to {
+		return
+	}
+	e.killed = append([]rune{}, e.buf[from:to]...)
+	e.remove(from, to)
+}
+
+func (e *LineEditor) remove(from, to int) {
+	e.buf = append(e.buf[:from], e.buf[to:]...)
+	e.pos = from
+}
+
+func (e *LineEditor) wordStart() int {
+	i := e.pos
+	for i > 0 && unicode.IsSpace(e.buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(e.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+func (e *LineEditor) wordEnd() int {
+	i := e.pos
+	for i < len(e.buf) && unicode.IsSpace(e.buf[i]) {
+		i++
+	}
+	for i < len(e.buf) && !unicode.IsSpace(e.buf[i]) {
+		i++
+	}
+	return i
+}
+
+// complete replaces the word before the cursor with the single
+// completion, or its longest common prefix when there are several
+// (listing them if nothing more could be completed)
+func (e *LineEditor) complete(prompt string) {
+	if e.Complete == nil {
+		return
+	}
+	matches := e.Complete(string(e.buf[:e.pos]))
+	if len(matches) == 0 {
+		return
+	}
+	start := e.pos
+	for start > 0 && !unicode.IsSpace(e.buf[start-1]) {
+		start--
+	}
+	word := string(e.buf[start:e.pos])
+	if len(matches) == 1 {
+		e.remove(start, e.pos)
+		e.insert([]rune(matches[0] + " ")...)
+		return
+	}
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(word) && strings.HasPrefix(prefix, word) {
+		e.remove(start, e.pos)
+		e.insert([]rune(prefix)...)
+		return
+	}
+	fmt.Fprint(e.Out, "\r\n"+strings.Join(matches, "  ")+"\r\n")
+}
@@ -0,0 +1,90 @@
+package term
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func edit(e *LineEditor, in string) (string, error) {
+	e.keys = NewKeyReader(strings.NewReader(in))
+	e.Out = new(bytes.Buffer)
+	return e.edit("> ")
+}
+
+func TestLineEditor_edit(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"hello\r", "hello"},
+		{"helo\x1b[Dl\r", "hello"},
+		{"ello\x01h\x05!\r", "hello!"},
+		{"hello world\x17there\r", "hello there"},
+		{"hello world\x01\x0b\x19\x19\r", "hello worldhello world"},
+		{"hello world\x1bb\x15\x05 \x19\r", "world hello "},
+		{"abc\x02\x02\x1b[3~\r", "ac"},
+		{"abc\x08\x7f\r", "a"},
+		{"one two\x1bb\x1bbX\r", "Xone two"},
+		{"\x1b[200~pasted\x1b[201~\r", "pasted"},
+	}
+	for _, test := range tests {
+		got, err := edit(new(LineEditor), test.in)
+		if err != nil || got != test.want {
+			t.Errorf("%q: want %q got %q (%v)", test.in, test.want, got, err)
+		}
+	}
+}
+
+func TestLineEditor_eof(t *testing.T) {
+	e := new(LineEditor)
+	if _, err := edit(e, "\x04"); err != io.EOF {
+		t.Errorf("want io.EOF got %v", err)
+	}
+	if _, err := edit(e, "abc\x03"); err != ErrInterrupt {
+		t.Errorf("want ErrInterrupt got %v", err)
+	}
+	if got, _ := edit(e, "ab\x02\x04\r"); got != "a" {
+		t.Errorf("Ctrl-D should delete, got %q", got)
+	}
+}
+
+func TestLineEditor_history(t *testing.T) {
+	e := &LineEditor{History: []string{"first", "second"}}
+	if got, _ := edit(e, "\x1b[A\x1b[A\r"); got != "first" {
+		t.Errorf("want first got %q", got)
+	}
+	if got, _ := edit(e, "new\x10\x0e\r"); got != "new" {
+		t.Errorf("want new got %q", got)
+	}
+	if len(e.History) != 4 || e.History[3] != "new" {
+		t.Errorf("history not appended: %q", e.History)
+	}
+}
+
+func TestLineEditor_complete(t *testing.T) {
+	e := &LineEditor{Complete: func(line string) []string {
+		words := strings.Fields(line)
+		if len(words) > 0 && strings.HasPrefix("greet", words[len(words)-1]) {
+			return []string{"greet"}
+		}
+		return []string{"french", "friendly"}
+	}}
+	if got, _ := edit(e, "gr\t\r"); got != "greet " {
+		t.Errorf("want 'greet ' got %q", got)
+	}
+	if got, _ := edit(e, "greet f\t\r"); got != "greet fr" {
+		t.Errorf("want 'greet fr' got %q", got)
+	}
+}
+
+func TestLineEditor_ReadLine_plain(t *testing.T) {
+	e := &LineEditor{In: strings.NewReader("one\ntwo")}
+	for _, want := range []string{"one", "two"} {
+		got, err := e.ReadLine("> ")
+		if err != nil || got != want {
+			t.Errorf("want %q got %q (%v)", want, got, err)
+		}
+	}
+	if _, err := e.ReadLine("> "); err != io.EOF {
+		t.Errorf("want io.EOF got %v", err)
+	}
+}
@@ -2,17 +2,69 @@ package term
 
 import (
 	"os"
+	"strconv"
+	"sync"
 )
 
 // WinSize is 80x24 by default but is detected and set to a more
 // accurate value at init() time on systems that support ioctl
 // (currently). This value can be overriden by those wishing a more
 // consistent value or who prefer not to fill the screen completely when
-// displaying help and usage information.
+// displaying help and usage information. The COLUMNS and LINES
+// environment variables always take priority over the detected values
+// when set. On systems that support it the size is updated again every
+// time the terminal window is resized (SIGWINCH) once Width or Height
+// have first been called (so that merely importing the package never
+// starts watching for the signal). Use Width and Height to read the
+// current values safely from concurrent code.
 var WinSize = &struct {
 	Row, Col       uint16
 	Xpixel, Ypixel uint16
-}{80, 24, 100, 100}
+}{24, 80, 100, 100}
+
+var winsizemu sync.Mutex
+
+var watching sync.Once
+
+func init() { UpdateWinSize() }
+
+// UpdateWinSize detects the current size of the terminal from standard
+// output (or standard input if output is not a terminal) and updates
+// WinSize. The COLUMNS and LINES environment variables override the
+// detected values when set to positive integers. UpdateWinSize is
+// called at init() time and whenever the window changes size (see
+// WinSize), but can be called again at any time.
+func UpdateWinSize() {
+	winsizemu.Lock()
+	defer winsizemu.Unlock()
+	if row, col, ok := winsize(); ok {
+		WinSize.Row, WinSize.Col = row, col
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		WinSize.Col = uint16(n)
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		WinSize.Row = uint16(n)
+	}
+}
+
+// Width returns the current number of columns of the terminal (see
+// WinSize) in a way that is safe for concurrency.
+func Width() int {
+	watching.Do(watchWinSize)
+	winsizemu.Lock()
+	defer winsizemu.Unlock()
+	return int(WinSize.Col)
+}
+
+// Height returns the current number of rows of the terminal (see
+// WinSize) in a way that is safe for concurrency.
+func Height() int {
+	watching.Do(watchWinSize)
+	winsizemu.Lock()
+	defer winsizemu.Unlock()
+	return int(WinSize.Row)
+}
 
 // IsTerminal returns true if the output is to an interactive terminal
 // (not piped in any way). This is useful when detemining if an extra
@@ -24,3 +76,10 @@ func IsTerminal() bool {
 	}
 	return false
 }
+
+// IsInputTerminal returns true if the input is from an interactive
+// terminal (not piped or redirected from a file or device). This is
+// useful when deciding whether to prompt for input or read it plainly.
+// Always false on systems where terminal modes are unsupported (see
+// MakeRaw).
+func IsInputTerminal() bool { return IsTerminalFd(int(os.Stdin.Fd())) }
@@ -38,3 +38,32 @@ func TestIsTerminal_true(t *testing.T) {
 		t.Error("terminal not connected")
 	}
 }
+
+func TestUpdateWinSize_env(t *testing.T) {
+	t.Cleanup(UpdateWinSize)
+	t.Setenv("COLUMNS", "123")
+	t.Setenv("LINES", "45")
+	UpdateWinSize()
+	if Width() != 123 || Height() != 45 {
+		t.Errorf("want 123x45, got %vx%v", Width(), Height())
+	}
+}
+
+func TestSupportsHyperlinks(t *testing.T) {
+	for _, v := range []string{"FORCE_HYPERLINK", "TERM_PROGRAM", "VTE_VERSION",
+		"WT_SESSION", "KONSOLE_VERSION", "TERM"} {
+		defer os.Setenv(v, os.Getenv(v))
+		os.Unsetenv(v)
+	}
+	if SupportsHyperlinks() {
+		t.Error("should not support hyperlinks by default")
+	}
+	os.Setenv("TERM", "xterm-kitty")
+	if !SupportsHyperlinks() {
+		t.Error("kitty supports hyperlinks")
+	}
+	os.Setenv("FORCE_HYPERLINK", "0")
+	if SupportsHyperlinks() {
+		t.Error("FORCE_HYPERLINK=0 should disable")
+	}
+}
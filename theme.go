@@ -0,0 +1,146 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/term"
+	"github.com/rwxrob/cmdbox/term/esc"
+	"github.com/rwxrob/cmdbox/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Theme contains the styles used by Help for the different parts of
+// the help documentation. Each is a style specification as accepted by
+// esc.Style ("bold", "208 on #202020", etc.) or a raw escape sequence
+// (such as those from the LESS_TERMCAP_* environment variables). Empty
+// styles are left plain. Styles are only ever applied when color is
+// enabled (see Color, ForceColor, and NO_COLOR). Also see HelpTheme and
+// LoadTheme.
+//
+type Theme struct {
+	Header string `json:"header,omitempty" yaml:",omitempty"`
+	Name   string `json:"name,omitempty" yaml:",omitempty"`
+	Usage  string `json:"usage,omitempty" yaml:",omitempty"`
+	Link   string `json:"link,omitempty" yaml:",omitempty"`
+}
+
+// DefaultTheme returns a new Theme with the default styles. Headers
+// observe the LESS_TERMCAP_md environment variable (like less and man)
+// if set.
+//
+func DefaultTheme() *Theme {
+	t := &Theme{
+		Header: "bold",
+		Name:   "bold",
+		Link:   "underline",
+	}
+	if md := os.Getenv("LESS_TERMCAP_md"); md != "" {
+		t.Header = md
+	}
+	return t
+}
+
+// HelpTheme is the Theme currently used by Help. It is loaded from the
+// file named by the CMDBOX_THEME environment variable at init() time if
+// set (see LoadTheme).
+//
+var HelpTheme = DefaultTheme()
+
+func init() {
+	if path := os.Getenv("CMDBOX_THEME"); path != "" {
+		if err := LoadTheme(path); err != nil && DEBUG {
+			util.Log(err)
+		}
+	}
+}
+
+// LoadTheme reads a YAML (or JSON) theme file and assigns it to
+// HelpTheme. Any styles not included in the file keep their default
+// values. An error is returned (and HelpTheme left unchanged) if the
+// file cannot be read or contains an unknown style.
+//
+//     header: bold underline
+//     name: bold 208
+//     usage: italic
+//     link: "underline #5f87ff"  # quoted since # begins a comment
+//
+func LoadTheme(path string) error {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	t := DefaultTheme()
+	if err := yaml.Unmarshal(byt, t); err != nil {
+		return err
+	}
+	for _, spec := range []string{t.Header, t.Name, t.Usage, t.Link} {
+		if _, err := style(spec); err != nil {
+			return fmt.Errorf("%v: %v", path, err)
+		}
+	}
+	HelpTheme = t
+	return nil
+}
+
+// Paint returns text wrapped in the style (see Theme) followed by
+// a reset if color is enabled. Otherwise, text is returned unaltered.
+func (t *Theme) Paint(spec, text string) string {
+	return t.paint(colorOn(), spec, text)
+}
+
+// paint is Paint with color on or off as given (for a Box with its own
+// color settings, see Command.paint).
+func (t *Theme) paint(on bool, spec, text string) string {
+	if spec == "" || !on {
+		return text
+	}
+	s, err := style(spec)
+	if err != nil || s == "" {
+		return text
+	}
+	return s + text + esc.Reset
+}
+
+// PaintLink returns the url painted with the Link style (see Paint)
+// and, if util.Hyperlinks is enabled and either color is enabled or
+// hyperlinks are forced (see term.HyperlinksForced), as an OSC 8
+// hyperlink so that it can be clicked. Otherwise, the plain url is all
+// that remains.
+//
+func (t *Theme) PaintLink(url string) string {
+	return t.paintLink(colorOn(), url)
+}
+
+// paintLink is PaintLink with color on or off as given.
+func (t *Theme) paintLink(on bool, url string) string {
+	if util.Hyperlinks && (on || term.HyperlinksForced()) {
+		return esc.Link(url, t.paint(on, t.Link, url))
+	}
+	return t.paint(on, t.Link, url)
+}
+
+// style allows raw escape sequences in place of specifications
+func style(spec string) (string, error) {
+	if strings.HasPrefix(spec, "\033") {
+		return spec, nil
+	}
+	return esc.Style(spec)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rwxrob/cmdbox"
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleTheme_Paint() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	t := cmdbox.DefaultTheme()
+	fmt.Printf("%q\n", t.Paint(t.Header, "NAME")) // not a terminal
+
+	cmdbox.ForceColor = true
+	defer func() { cmdbox.ForceColor = false }()
+	fmt.Printf("%q\n", t.Paint(t.Header, "NAME"))
+	fmt.Printf("%q\n", t.Paint("yellow on blue", "NAME"))
+	fmt.Printf("%q\n", t.Paint("", "NAME"))
+
+	// Output:
+	// "NAME"
+	// "\x1b[1mNAME\x1b[0m"
+	// "\x1b[33m\x1b[44mNAME\x1b[0m"
+	// "NAME"
+}
+
+func ExampleLoadTheme() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	defer func() { cmdbox.HelpTheme = cmdbox.DefaultTheme() }()
+
+	fmt.Println(cmdbox.LoadTheme("testdata/badtheme.yaml"))
+	fmt.Println(cmdbox.LoadTheme("testdata/theme.yaml"))
+	fmt.Printf("%q\n", *cmdbox.HelpTheme)
+
+	// Output:
+	// testdata/badtheme.yaml: unknown style: purple
+	// <nil>
+	// {"bold underline" "208" "" "underline #5f87ff"}
+}
+
+func ExampleTheme_PaintLink() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	t := cmdbox.DefaultTheme()
+	fmt.Printf("%q\n", t.PaintLink("https://example.com"))
+
+	util.Hyperlinks = true
+	defer func() { util.Hyperlinks = false }()
+	fmt.Printf("%q\n", t.PaintLink("https://example.com")) // no color
+
+	os.Setenv("FORCE_HYPERLINK", "1")
+	fmt.Printf("%q\n", t.PaintLink("https://example.com"))
+	os.Unsetenv("FORCE_HYPERLINK")
+
+	cmdbox.ForceColor = true
+	defer func() { cmdbox.ForceColor = false }()
+	fmt.Printf("%q\n", t.PaintLink("https://example.com"))
+
+	// Output:
+	// "https://example.com"
+	// "https://example.com"
+	// "\x1b]8;;https://example.com\x1b\\https://example.com\x1b]8;;\x1b\\"
+	// "\x1b]8;;https://example.com\x1b\\\x1b[4mhttps://example.com\x1b[0m\x1b]8;;\x1b\\"
+}
+
+func ExampleCommand_PrintHelp_forcedLinks() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+	util.Hyperlinks = true
+	defer func() { util.Hyperlinks = false }()
+	os.Setenv("FORCE_HYPERLINK", "1")
+	defer os.Unsetenv("FORCE_HYPERLINK")
+
+	var out strings.Builder
+	b := cmdbox.NewBox()
+	b.Stdout = &out
+	x := b.Add("foo")
+	x.Site = "https://example.com"
+	x.PrintHelp() // not a terminal, no color
+
+	fmt.Println(strings.Contains(out.String(), "\x1b]8;;https://example.com\x1b\\"))
+	fmt.Println(strings.Contains(out.String(), "\x1b["))
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleCommand_Help_emphasis() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "bar")
+	x.Description = `Very **simple**.`
+	cmdbox.Add("foo bar").Summary = "a bar"
+
+	// description, command name, and subcommand title all styled or not
+	styled := func(x *cmdbox.Command) string {
+		help := x.Help()
+		return fmt.Sprint(strings.Contains(help, "\x1b[1msimple"),
+			strings.Contains(help, "foo\x1b[0m"),
+			strings.Contains(help, "bar\x1b[0m"))
+	}
+
+	fmt.Println(styled(x)) // not a terminal
+
+	b := cmdbox.NewBox()
+	b.ForceColor = true
+	y := b.Add("foo", "bar")
+	y.Description = x.Description
+	b.Add("foo bar").Summary = "a bar"
+	fmt.Println(styled(y))
+
+	cmdbox.ForceColor = true
+	defer func() { cmdbox.ForceColor = false }()
+	fmt.Println(styled(x))
+	b.ForceColor, b.Color = false, false
+	fmt.Println(styled(y))
+
+	// Output:
+	// false false false
+	// true true true
+	// true true true
+	// false false false
+}
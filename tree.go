@@ -0,0 +1,181 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+// Node is a single entry in the tree of Commands returned by
+// Command.Tree. Name is the subcommand name (the value in the Commands
+// of its parent) and Command is the name of the Command it resolved to
+// in the register (empty when Unresolved). Hidden is set when the
+// parent lists it in Hidden and Unavailable is the reason it cannot be
+// used right now (see Command.Available).
+//
+type Node struct {
+	Name        string   `json:"name"`
+	Command     string   `json:"command,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Hidden      bool     `json:"hidden,omitempty"`
+	Unresolved  bool     `json:"unresolved,omitempty"`
+	Unavailable string   `json:"unavailable,omitempty"`
+	Commands    []*Node  `json:"commands,omitempty"`
+}
+
+// Tree returns the whole hierarchy of Commands under x by following
+// each of its Commands (resolving qualified names first, see
+// Command.Resolve) and theirs in turn. Hidden (and unavailable)
+// subcommands are only included when hidden is true and those not
+// (yet) in the register only when unresolved is true. A Command already
+// being walked is listed again without its subcommands rather than
+// looping forever. Note that every Command added with AddLazy is built
+// in order to find its subcommands.
+//
+func (x *Command) Tree(hidden, unresolved bool) *Node {
+	x.load()
+	n := &Node{Name: x.Name, Command: x.Name, Summary: x.Summary}
+	n.Commands = x.tree(map[*Command]bool{x: true}, hidden, unresolved)
+	return n
+}
+
+func (x *Command) tree(walking map[*Command]bool,
+	hidden, unresolved bool) []*Node {
+	nodes := []*Node{}
+	for _, name := range x.Commands.Values() {
+		n := &Node{Name: name, Aliases: x.Commands.AliasesFor(name)}
+		n.Hidden = util.InSlice(name, x.Hidden)
+		if n.Hidden && !hidden {
+			continue
+		}
+		c := x.Resolve(name)
+		if c == nil {
+			if unresolved {
+				n.Unresolved = true
+				nodes = append(nodes, n)
+			}
+			continue
+		}
+		c.load()
+		if c.Available != nil {
+			if err := c.Available(); err != nil {
+				if !hidden {
+					continue
+				}
+				n.Unavailable = err.Error()
+			}
+		}
+		n.Command, n.Summary = c.Name, c.badge()+c.Summary
+		if !walking[c] {
+			walking[c] = true
+			n.Commands = c.tree(walking, hidden, unresolved)
+			delete(walking, c)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// sig returns the aliases and name combined as in Command.Titles.
+func (n *Node) sig() string {
+	return strings.Join(append(n.Aliases, n.Name), "|")
+}
+
+// String renders the tree as text with each level indented two spaces
+// beneath its parent and the summaries lined up (and truncated to fit
+// within HelpWidth). Hidden and unavailable subcommands are marked as
+// such and unresolved ones are shown as not yet implemented.
+//
+func (n *Node) String() string {
+	var width int
+	var measure func(m *Node, depth int)
+	measure = func(m *Node, depth int) {
+		if w := depth*2 + len(m.sig()); w > width {
+			width = w
+		}
+		for _, c := range m.Commands {
+			measure(c, depth+1)
+		}
+	}
+	measure(n, 0)
+
+	var buf string
+	var render func(m *Node, depth int)
+	render = func(m *Node, depth int) {
+		summary := m.Summary
+		switch {
+		case m.Unresolved:
+			summary = "<not yet implemented>"
+		case m.Unavailable != "":
+			summary = "(unavailable) " + summary
+		case m.Hidden:
+			summary = "(hidden) " + summary
+		}
+		line := fmt.Sprintf("%-"+fmt.Sprint(width)+"v",
+			strings.Repeat("  ", depth)+m.sig())
+		if summary != "" {
+			line += " - " + truncate(summary, HelpWidth()-width-3)
+		}
+		buf += strings.TrimRight(line, " ") + "\n"
+		for _, c := range m.Commands {
+			render(c, depth+1)
+		}
+	}
+	render(n, 0)
+	return buf
+}
+
+// AddTree adds a tree subcommand (also called commands) that prints the
+// whole hierarchy of Commands under x (see Command.Tree), usually added
+// to the main command. The words hidden and unresolved (or all for
+// both) include those subcommands as well. The tree is printed as text
+// on a terminal and as JSON otherwise (or in any of the Formats named
+// by a trailing word, see OutputFormat).
+//
+func (x *Command) AddTree() {
+	x.Add("commands|tree")
+	t := x.Box().Add(x.Name + " tree")
+	t.Usage = `[hidden|unresolved|all]...`
+	t.Summary = `list every command and subcommand`
+	t.Description = `
+		Prints the whole tree of commands and subcommands with their
+		aliases and summaries. Add hidden to include hidden (and currently
+		unavailable) subcommands, unresolved to include those not yet
+		implemented, or all for both.`
+	t.Result = func(args ...string) (interface{}, error) {
+		var hidden, unresolved bool
+		for _, arg := range args {
+			switch arg {
+			case "hidden":
+				hidden = true
+			case "unresolved":
+				unresolved = true
+			case "all":
+				hidden, unresolved = true, true
+			default:
+				return nil, t.UsageError()
+			}
+		}
+		return x.Tree(hidden, unresolved), nil
+	}
+	x.UpdateUsage()
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleCommand_Tree() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("greet", "fr|french", "ru|russian", "secret", "todo")
+	x.Summary = "greet in many languages"
+	x.Hidden = []string{"secret"}
+	fr := cmdbox.Add("greet french", "formal")
+	fr.Summary = "greet in French"
+	cmdbox.Add("greet french formal").Summary = "greet formally"
+	cmdbox.Add("greet russian").Summary = "greet in Russian"
+	cmdbox.Add("greet secret").Summary = "the secret greeting"
+
+	fmt.Print(x.Tree(false, false))
+	fmt.Print(x.Tree(true, true))
+
+	// Output:
+	// greet        - greet in many languages
+	//   fr|french  - greet in French
+	//     formal   - greet formally
+	//   ru|russian - greet in Russian
+	// greet        - greet in many languages
+	//   fr|french  - greet in French
+	//     formal   - greet formally
+	//   ru|russian - greet in Russian
+	//   secret     - (hidden) the secret greeting
+	//   todo       - <not yet implemented>
+}
+
+func ExampleCommand_AddTree() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	x := cmdbox.Add("foo", "b|bar")
+	x.AddTree()
+	cmdbox.Add("foo bar").Summary = "does bar"
+
+	cmdbox.Call(x, "foo", "commands", "json")
+
+	// Output:
+	// {
+	//     "name": "foo",
+	//     "command": "foo",
+	//     "commands": [
+	//       {
+	//         "name": "bar",
+	//         "command": "foo bar",
+	//         "aliases": [
+	//           "b"
+	//         ],
+	//         "summary": "does bar"
+	//       },
+	//       {
+	//         "name": "tree",
+	//         "command": "foo tree",
+	//         "aliases": [
+	//           "commands"
+	//         ],
+	//         "summary": "list every command and subcommand"
+	//       }
+	//     ]
+	//   }
+}
@@ -21,8 +21,36 @@ var bold = esc.Bold
 var bolditalic = esc.BoldItalic
 var underline = esc.Underline
 
+// Hyperlinks enables OSC 8 hyperlinks (see esc.Link) for any http:// or
+// https:// URL within angle brackets (<https://...>) passed to Emphasize
+// (and therefore Emph). It is set at init() time if FORCE_HYPERLINK is
+// set (see term.HyperlinksForced) or output is to an interactive
+// terminal that supports them (see term.SupportsHyperlinks) but can be
+// forced on or off by assignment (when a configuration or pager is
+// known to support them, for example).
+//
+var Hyperlinks bool
+
+// styles are the escapes used for emphasis (see Emphasize).
+type styles struct {
+	reset, italic, bold, bolditalic, underline string
+}
+
+// current returns the styles currently in use (all empty when output is
+// not to an interactive terminal or NO_COLOR is set).
+func current() styles {
+	return styles{reset, italic, bold, bolditalic, underline}
+}
+
+// styled holds the styles (observing LESS_TERMCAP_*) whether or not
+// they are currently in use (see EmphIf).
+var styled styles
+
 func init() {
-	if !term.IsTerminal() {
+	emphFromLess()
+	styled = current()
+	Hyperlinks = term.HyperlinksForced()
+	if !term.IsTerminal() || os.Getenv("NO_COLOR") != "" {
 		reset = ""
 		italic = ""
 		bold = ""
@@ -30,7 +58,7 @@ func init() {
 		underline = ""
 		return
 	}
-	emphFromLess()
+	Hyperlinks = Hyperlinks || term.SupportsHyperlinks()
 }
 
 func emphFromLess() {
@@ -77,7 +105,8 @@ func emphFromLess() {
 //
 // * URL links argument names and anything else within angle brackets
 //   (<url>), will trigger underline in both text blocks
-//   and usage sections.
+//   and usage sections. URLs will also be hyperlinks if Hyperlinks is
+//   enabled.
 //
 // * Italic, Bold, and BoldItalic inline emphasis using one, two, or
 //   three stars respectivly will be observed and cannot be intermixed or
@@ -95,7 +124,28 @@ func emphFromLess() {
 //   * BoldItalic  LESS_TERMCAP_mb
 //   * Underline   LESS_TERMCAP_us
 //
-func Emph(input string, indent, width int) (output string) {
+// Emphasis is only added when output is to an interactive terminal and
+// the NO_COLOR environment variable is not set. See EmphIf.
+//
+func Emph(input string, indent, width int) string {
+	return emph(input, indent, width, current(), Hyperlinks)
+}
+
+// EmphIf is the same as Emph but adds the emphasis (and hyperlinks, if
+// Hyperlinks is enabled) only if on is true no matter where output is
+// going (so that callers can apply their own color settings). Forced
+// hyperlinks (see term.HyperlinksForced) are added either way.
+//
+func EmphIf(on bool, input string, indent, width int) string {
+	if !on {
+		links := Hyperlinks && term.HyperlinksForced()
+		return emph(input, indent, width, styles{}, links)
+	}
+	return emph(input, indent, width, styled, Hyperlinks)
+}
+
+func emph(input string, indent, width int, s styles,
+	links bool) (output string) {
 
 	// this scanner could be waaaay more lexy
 	// but suits the need and clear to read
@@ -182,14 +232,14 @@ func Emph(input string, indent, width int) (output string) {
 		// end block
 		if inblock && len(trimmed) == 0 {
 			inblock = false
-			output += "\n\n" + Emphasize(Wrap(blockbuf, width-strip-4))
+			output += "\n\n" + emphasize(Wrap(blockbuf, width-strip-4), s, links)
 			continue
 		}
 	}
 
 	// flush last block
 	if inblock {
-		output += "\n\n" + Emphasize(Wrap(blockbuf, width-strip-4))
+		output += "\n\n" + emphasize(Wrap(blockbuf, width-strip-4), s, links)
 	}
 	output = Indent(strings.TrimSpace(output), indent)
 	return
@@ -198,6 +248,10 @@ func Emph(input string, indent, width int) (output string) {
 // Emphasize replaces minimal Markdown-like syntax with *Italic*,
 // **Bold**, ***BoldItalic***, and <bracketed>
 func Emphasize(buf string) string {
+	return emphasize(buf, current(), Hyperlinks)
+}
+
+func emphasize(buf string, s styles, links bool) string {
 
 	// italic = `<italic>`
 	// bold = `<bold>`
@@ -215,7 +269,8 @@ func Emphasize(buf string) string {
 
 		if r == '<' {
 			nbuf = append(nbuf, '<')
-			nbuf = append(nbuf, []rune(underline)...)
+			nbuf = append(nbuf, []rune(s.underline)...)
+			inner := []rune{}
 			for {
 				i++
 				r = rune(buf[i])
@@ -223,9 +278,13 @@ func Emphasize(buf string) string {
 					i++
 					break
 				}
-				nbuf = append(nbuf, r)
+				inner = append(inner, r)
+			}
+			if links && isURL(string(inner)) {
+				inner = []rune(esc.Link(string(inner), string(inner)))
 			}
-			nbuf = append(nbuf, []rune(reset)...)
+			nbuf = append(nbuf, inner...)
+			nbuf = append(nbuf, []rune(s.reset)...)
 			nbuf = append(nbuf, '>')
 			i--
 			continue
@@ -238,11 +297,11 @@ func Emphasize(buf string) string {
 				if !unicode.IsSpace(r) {
 					switch otok {
 					case "*":
-						tokval = italic
+						tokval = s.italic
 					case "**":
-						tokval = bold
+						tokval = s.bold
 					case "***":
-						tokval = bolditalic
+						tokval = s.bolditalic
 					}
 				} else {
 					tokval = otok
@@ -253,7 +312,7 @@ func Emphasize(buf string) string {
 			}
 
 			if closetok {
-				nbuf = append(nbuf, []rune(reset)...) // practical, not perfect
+				nbuf = append(nbuf, []rune(s.reset)...) // practical, not perfect
 				ctok = ""
 				closetok = false
 			}
@@ -284,12 +343,18 @@ func Emphasize(buf string) string {
 
 	// for tokens at the end of a block
 	if closetok {
-		nbuf = append(nbuf, []rune(reset)...)
+		nbuf = append(nbuf, []rune(s.reset)...)
 	}
 
 	return string(nbuf)
 }
 
+// isURL returns true for anything that begins with http:// or https://.
+func isURL(buf string) bool {
+	return strings.HasPrefix(buf, "https://") ||
+		strings.HasPrefix(buf, "http://")
+}
+
 // Indent indents each line the set number of spaces.
 func Indent(buf string, spaces int) string {
 	nbuf := ""
@@ -2,6 +2,8 @@ package util
 
 import (
 	"testing"
+
+	"github.com/rwxrob/cmdbox/term/esc"
 )
 
 func TestEmphasize(t *testing.T) {
@@ -66,3 +68,25 @@ func TestPlain(t *testing.T) {
 		t.Errorf("\nwant:\n%q\ngot:\n%q\n", want, got)
 	}
 }
+
+func TestEmphasize_hyperlinks(t *testing.T) {
+	defer func(h bool) { Hyperlinks = h }(Hyperlinks)
+	Hyperlinks = true
+	url := "https://github.com/rwxrob/cmdbox"
+	want := "see <" + underline + esc.Link(url, url) + reset + "> and <" +
+		underline + "other" + reset + ">"
+	got := Emphasize("see <" + url + "> and <other>")
+	if got != want {
+		t.Errorf("\nwant: %q\ngot:  %q\n", want, got)
+	}
+}
+
+func TestEmphIf(t *testing.T) {
+	want := styled.bold + "simple" + styled.reset
+	if got := EmphIf(true, "**simple**", 0, 70); got != want {
+		t.Errorf("\nwant: %q\ngot:  %q\n", want, got)
+	}
+	if got := EmphIf(false, "**simple**", 0, 70); got != "simple" {
+		t.Errorf("\nwant: %q\ngot:  %q\n", "simple", got)
+	}
+}
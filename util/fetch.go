@@ -5,14 +5,24 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
+
+	"github.com/rwxrob/cmdbox/term"
 )
 
+// FetchProgress enables a progress bar (see term.Bar) on standard error
+// while Fetch downloads, which is drawn in place on interactive
+// terminals and written as periodic plain lines otherwise.
+//
+var FetchProgress bool
+
 // Fetch is the equivalent of curl -sSL or wget to simply fetch a file
 // from the Web. The first argument is always the URL. The second
 // argument is the path to local file to which to write the downloaded
 // file. Note that if the file exists it will be overwritten. The third
 // argument is the maximum number of seconds to wait for a response.
+// Fetch is silent unless FetchProgress is enabled.
 //
 func Fetch(url, local string, timeout time.Duration) error {
 	var err error
@@ -24,15 +34,22 @@ func Fetch(url, local string, timeout time.Duration) error {
 		return err
 	}
 	res, err := http.DefaultClient.Do(req)
-	defer res.Body.Close()
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 	file, err := os.Create(local)
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(file, res.Body)
+	defer file.Close()
+	var body io.Reader = res.Body
+	if FetchProgress {
+		bar := term.NewBar(filepath.Base(local), res.ContentLength)
+		defer bar.Finish()
+		body = bar.Reader(body)
+	}
+	_, err = io.Copy(file, body)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/term"
+)
+
+// PagerOff disables all paging done with Page (which then simply
+// prints). It is set to true at init() time if the CMDBOX_NOPAGER
+// environment variable is set to anything.
+//
+var PagerOff bool
+
+func init() {
+	if os.Getenv("CMDBOX_NOPAGER") != "" {
+		PagerOff = true
+	}
+}
+
+// Pager returns the command (and any arguments) that Page will use. The
+// PAGER environment variable is split on white space and used if set.
+// Otherwise, less -R is used if less can be found in the PATH. An empty
+// slice is returned if no pager can be found.
+//
+func Pager() []string {
+	if p := strings.Fields(os.Getenv("PAGER")); len(p) > 0 {
+		return p
+	}
+	if _, err := exec.LookPath("less"); err == nil {
+		return []string{"less", "-R"}
+	}
+	return []string{}
+}
+
+// Page prints buf to standard output through the Pager but only if
+// standard output is an interactive terminal and buf has more lines than
+// will fit within term.Height. Otherwise, buf is printed directly.
+// The LESS environment variable is set to R (when not already set) so
+// that colors and emphasis pass through less unaltered. If the pager
+// cannot be started buf is printed directly as well. See PagerOff.
+//
+func Page(buf string) error {
+	if PagerOff || !term.IsTerminal() ||
+		strings.Count(buf, "\n") < term.Height() {
+		fmt.Print(buf)
+		return nil
+	}
+	pager := Pager()
+	if len(pager) == 0 {
+		fmt.Print(buf)
+		return nil
+	}
+	path, err := exec.LookPath(pager[0])
+	if err != nil {
+		fmt.Print(buf)
+		return nil
+	}
+	cmd := exec.Command(path, pager[1:]...)
+	cmd.Stdin = strings.NewReader(buf)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(cmd.Env, "LESS=R")
+	}
+	return cmd.Run()
+}
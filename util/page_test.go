@@ -0,0 +1,51 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExamplePage() {
+	// not an interactive terminal so never paged
+	util.Page("some\nlines\n")
+
+	// Output:
+	// some
+	// lines
+}
+
+func ExampleStripEsc() {
+	fmt.Println(util.StripEsc("\033[1mbold\033[0m and \033[38;5;208mcolor\033[0m"))
+	fmt.Println(util.StripEsc("\033]8;;https://example.com\033\\link\033]8;;\033\\"))
+
+	// Output:
+	// bold and color
+	// link
+}
+
+func ExampleStripEscKeepLinks() {
+	link := "\033]8;;https://example.com\033\\\033[4mlink\033[0m\033]8;;\033\\"
+	fmt.Printf("%q\n", util.StripEscKeepLinks(link))
+	fmt.Printf("%q\n", util.StripEscKeepLinks("\033]0;title\007plain"))
+
+	// Output:
+	// "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\"
+	// "plain"
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "unicode"
+
+// SplitArgs splits a line into arguments on white space much like
+// a shell would. Single and double quotes group words containing white
+// space into a single argument (and are removed) and backslash escapes
+// the rune following it (except within single quotes). Unterminated
+// quotes are ended at the end of the line. No other shell expansion of
+// any kind is done.
+//
+func SplitArgs(line string) []string {
+	args := []string{}
+	arg := []rune{}
+	inarg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg = append(arg, r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inarg = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg = append(arg, r)
+		case r == '"' || r == '\'':
+			quote = r
+			inarg = true
+		case unicode.IsSpace(r):
+			if inarg {
+				args = append(args, string(arg))
+				arg = []rune{}
+				inarg = false
+			}
+		default:
+			arg = append(arg, r)
+			inarg = true
+		}
+	}
+	if inarg {
+		args = append(args, string(arg))
+	}
+	return args
+}
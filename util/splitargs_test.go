@@ -0,0 +1,34 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleSplitArgs() {
+	fmt.Printf("%q\n", util.SplitArgs(`  greet  "Mr. Rob"   'it''s' \"hi\" one\ arg ""`))
+	fmt.Printf("%q\n", util.SplitArgs(""))
+	fmt.Printf("%q\n", util.SplitArgs(`"unterminated quote`))
+
+	// Output:
+	// ["greet" "Mr. Rob" "its" "\"hi\"" "one arg" ""]
+	// []
+	// ["unterminated quote"]
+}
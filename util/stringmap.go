@@ -27,13 +27,14 @@ import (
 // StringMap is a high-level type used to contain string data for both
 // keys and values Developers can use the StringMap type in their own
 // modules as a convenience since requires less declaration and is 100%
-// safe for concurrency (embeds synx.Mutex). The internal map is
+// safe for concurrency (embeds sync.RWMutex so that any number of
+// queries may run at once). The internal map is
 // exported (as M) for when developers want to do their own locking and
 // mutations rather than use the public interface methods.
 //
 type StringMap struct {
 	M map[string]string
-	sync.Mutex
+	sync.RWMutex
 }
 
 // NewStringMap returns a new StringMap with the internal
@@ -75,8 +76,8 @@ func (m *StringMap) Init() {
 // Get returns a value by key name safe for concurrency. Returns empty
 // string if not found.
 func (m *StringMap) Get(key string) string {
-	defer m.Unlock()
-	m.Lock()
+	m.RLock()
+	defer m.RUnlock()
 	if v, has := m.M[key]; has {
 		return v
 	}
@@ -116,9 +117,9 @@ func (m *StringMap) Rename(from, to string) {
 // ------------------------------ queries -----------------------------
 
 // Same returns a sorted list of all keys that are also values.
-func (m StringMap) Same() []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) Same() []string {
+	m.RLock()
+	defer m.RUnlock()
 	same := []string{}
 	for k, v := range m.M {
 		if k == v {
@@ -130,9 +131,9 @@ func (m StringMap) Same() []string {
 }
 
 // Keys returns a sorted list of all possible string keys.
-func (m StringMap) Keys() []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) Keys() []string {
+	m.RLock()
+	defer m.RUnlock()
 	keys := make([]string, len(m.M))
 	var i int
 	for k, _ := range m.M {
@@ -144,15 +145,15 @@ func (m StringMap) Keys() []string {
 }
 
 // KeysWithout is same as Keys but omits a list of strings.
-func (m StringMap) KeysWithout(omit []string) []string {
+func (m *StringMap) KeysWithout(omit []string) []string {
 	return OmitFromSlice(m.Keys(), omit)
 }
 
 // Values returns a sorted list of all unique values safe for
 // concurrency.
-func (m StringMap) Values() []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) Values() []string {
+	m.RLock()
+	defer m.RUnlock()
 	vals := []string{}
 	seen := map[string]bool{}
 	for _, v := range m.M {
@@ -167,9 +168,9 @@ func (m StringMap) Values() []string {
 }
 
 // Aliases returns only the keys that are not identical to their value.
-func (m StringMap) Aliases() []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) Aliases() []string {
+	m.RLock()
+	defer m.RUnlock()
 	a := []string{}
 	for k, v := range m.M {
 		if k == v {
@@ -183,9 +184,9 @@ func (m StringMap) Aliases() []string {
 
 // AliasesFor returns only the Aliases that point to a specific value.
 // This does not include keys that are identical to their value.
-func (m StringMap) AliasesFor(val string) []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) AliasesFor(val string) []string {
+	m.RLock()
+	defer m.RUnlock()
 	aliases := []string{}
 	for k, v := range m.M {
 		if k != v && v == val {
@@ -199,9 +200,9 @@ func (m StringMap) AliasesFor(val string) []string {
 // KeysFor returns only the Keys that point to a specific value.
 // This includes keys that are identical to their value, which will
 // always be last. The rest will be sorted.
-func (m StringMap) KeysFor(val string) []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) KeysFor(val string) []string {
+	m.RLock()
+	defer m.RUnlock()
 	keys := []string{}
 	hasSelf := false
 	for k, v := range m.M {
@@ -221,7 +222,7 @@ func (m StringMap) KeysFor(val string) []string {
 }
 
 // KeysForWithout is same as KeysFor but omits a list of strings.
-func (m StringMap) KeysForWithout(val string, omit []string) []string {
+func (m *StringMap) KeysForWithout(val string, omit []string) []string {
 	return OmitFromSlice(m.KeysFor(val), omit)
 }
 
@@ -229,7 +230,7 @@ func (m StringMap) KeysForWithout(val string, omit []string) []string {
 // point to the same value sorted, combined, and delimited into a single
 // value per unique value as the key. This is useful for creating
 // alternative option strings. Also see AliasesFor and KeysCombined.
-func (m StringMap) AliasesCombined(delim string) *StringMap {
+func (m *StringMap) AliasesCombined(delim string) *StringMap {
 	n := NewStringMap()
 	n.Lock()
 	for _, name := range m.Values() {
@@ -244,7 +245,7 @@ func (m StringMap) AliasesCombined(delim string) *StringMap {
 // per unique value set to the key. If any key equals the value it will
 // automatically appear last in the delimited list. This is useful for
 // creating alternative option strings. Also see KeysFor.
-func (m StringMap) KeysCombined(delim string) *StringMap {
+func (m *StringMap) KeysCombined(delim string) *StringMap {
 	n := NewStringMap()
 	n.Lock()
 	for _, name := range m.Values() {
@@ -256,7 +257,7 @@ func (m StringMap) KeysCombined(delim string) *StringMap {
 
 // KeysWithoutCombined is same as KeysCombined but omits a list of
 // strings.
-func (m StringMap) KeysCombinedWithout(delim string, omit []string) *StringMap {
+func (m *StringMap) KeysCombinedWithout(delim string, omit []string) *StringMap {
 	n := NewStringMap()
 	n.Lock()
 	for _, name := range m.Values() {
@@ -269,9 +270,9 @@ func (m StringMap) KeysCombinedWithout(delim string, omit []string) *StringMap {
 // Slice returns a slice of values fetched from the StringMap in order
 // that match the key names passed. If a name is not found its value
 // will be an empty string. Slice is safe for concurrency.
-func (m StringMap) Slice(keys ...string) []string {
-	defer m.Unlock()
-	m.Lock()
+func (m *StringMap) Slice(keys ...string) []string {
+	m.RLock()
+	defer m.RUnlock()
 	vals := make([]string, len(keys))
 	for i, k := range keys {
 		vals[i] = m.M[k]
@@ -282,10 +283,12 @@ func (m StringMap) Slice(keys ...string) []string {
 // HasSuffix returns a new StringMap containing only those entries that
 // have values with the specified suffix. HasSuffix is safe for
 // concurrency. See strings.HasSuffix.
-func (m StringMap) HasSuffix(s string) *StringMap {
+func (m *StringMap) HasSuffix(s string) *StringMap {
 	n := NewStringMap()
 	defer n.Unlock()
 	n.Lock()
+	m.RLock()
+	defer m.RUnlock()
 	for k, v := range m.M {
 		if strings.HasSuffix(v, s) {
 			n.M[k] = v
@@ -297,10 +300,12 @@ func (m StringMap) HasSuffix(s string) *StringMap {
 // HasPrefix returns a new StringMap containing only those entries that
 // have values with the specified prefis. HasPrefix is safe for
 // concurrency. See strings.HasPrefix.
-func (m StringMap) HasPrefix(s string) *StringMap {
+func (m *StringMap) HasPrefix(s string) *StringMap {
 	n := NewStringMap()
 	defer n.Unlock()
 	n.Lock()
+	m.RLock()
+	defer m.RUnlock()
 	for k, v := range m.M {
 		if strings.HasPrefix(v, s) {
 			n.M[k] = v
@@ -311,7 +316,9 @@ func (m StringMap) HasPrefix(s string) *StringMap {
 
 // LongestKey returns the key and value with the longest key. The first
 // longest key will win and Go maps to not promise any specific order.
-func (m StringMap) LongestKey() (string, string) {
+func (m *StringMap) LongestKey() (string, string) {
+	m.RLock()
+	defer m.RUnlock()
 	longest := ""
 	longestv := ""
 	for k, v := range m.M {
@@ -326,7 +333,9 @@ func (m StringMap) LongestKey() (string, string) {
 // LongestValue returns the key and value with the longest value. The
 // first longest value will win and Go maps to not promise any specific
 // order.
-func (m StringMap) LongestValue() (string, string) {
+func (m *StringMap) LongestValue() (string, string) {
+	m.RLock()
+	defer m.RUnlock()
 	longest := ""
 	longestv := ""
 	for k, v := range m.M {
@@ -341,25 +350,39 @@ func (m StringMap) LongestValue() (string, string) {
 // ---------------------------- marshaling ----------------------------
 
 // RawJSON calls MustRawJSON on the internal map.
-func (m StringMap) RawJSON() string { return MustRawJSON(m.M) }
+func (m *StringMap) RawJSON() string {
+	m.RLock()
+	defer m.RUnlock()
+	return MustRawJSON(m.M)
+}
 
 // JSON calls MustJSON on the internal map. It is often more convenient
 // to simply print/Print instead since the String (from fmt.Stringer
 // interface) does the same thing.
-func (m StringMap) JSON() string { return MustJSON(m.M) }
+func (m *StringMap) JSON() string {
+	m.RLock()
+	defer m.RUnlock()
+	return MustJSON(m.M)
+}
 
 // String fulfills fmt.Stringer interface as JSON.
-func (m StringMap) String() string { return MustJSON(m.M) }
+func (m *StringMap) String() string { return m.JSON() }
 
 // Print outputs as JSON (nice when testing).
-func (m StringMap) Print() { fmt.Println(MustJSON(m.M)) }
+func (m *StringMap) Print() { fmt.Println(m.JSON()) }
 
 // MarshalJSON implements the json.Marshaler interface using the
 // internal (M) map.
-func (m StringMap) MarshalJSON() ([]byte, error) { return json.MarshalIndent(m.M, "  ", "  ") }
+func (m *StringMap) MarshalJSON() ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	return json.MarshalIndent(m.M, "  ", "  ")
+}
 
 // UnmarshalJSON implements the json.Unmarshaler interface using the
 // internal (M) map.
 func (m *StringMap) UnmarshalJSON(data []byte) error {
+	m.Lock()
+	defer m.Unlock()
 	return json.Unmarshal(data, &m.M)
 }
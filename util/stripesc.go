@@ -0,0 +1,36 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "regexp"
+
+var escapes = regexp.MustCompile(
+	"\x1b\\[[0-9;?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(\x07|\x1b\\\\)")
+
+// StripEsc returns buf with all terminal escape sequences (colors,
+// emphasis, cursor movement, hyperlinks, etc.) removed.
+func StripEsc(buf string) string { return escapes.ReplaceAllString(buf, "") }
+
+var nonlinks = regexp.MustCompile("\x1b\\[[0-9;?]*[ -/]*[@-~]|" +
+	"\x1b\\]([^8\x07\x1b]|8[^;\x07\x1b])[^\x07\x1b]*(\x07|\x1b\\\\)")
+
+// StripEscKeepLinks is the same as StripEsc but keeps OSC 8 hyperlinks
+// (see esc.Link) for when they are wanted without any other escapes
+// (see term.HyperlinksForced).
+func StripEscKeepLinks(buf string) string {
+	return nonlinks.ReplaceAllString(buf, "")
+}
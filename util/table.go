@@ -0,0 +1,343 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"github.com/rwxrob/cmdbox/term"
+)
+
+// Align is the alignment of the cells in a Table column.
+type Align int
+
+const (
+	Left Align = iota
+	Right
+	Center
+)
+
+// Table holds rows of string cells for rendering as aligned columns for
+// humans (String) or as TSV, CSV, JSON, or Markdown for other programs.
+// Cells may contain terminal escapes, which are ignored when measuring,
+// and East Asian wide runes, which count as two columns (see
+// DisplayWidth).
+//
+// Header is optional. When set, it is printed first (in bold when
+// output is to a terminal) and is used for the keys of JSON objects and
+// the Markdown header row.
+//
+// Align sets the alignment of each column by index. Columns without an
+// entry are aligned Left.
+//
+// Width is the maximum total width of the String rendering. Zero means
+// the current width of the terminal (see term.Width) and a negative
+// width means unlimited. When the columns do not fit, the widest are
+// narrowed first and the cells that no longer fit are either truncated
+// with an ellipsis (see Truncate) or, if Wrap is set, wrapped onto
+// additional lines.
+//
+// Sep is placed between columns in the String rendering and defaults to
+// two spaces.
+//
+type Table struct {
+	Header []string
+	Rows   [][]string
+	Align  []Align
+	Width  int
+	Wrap   bool
+	Sep    string
+}
+
+// NewTable returns a new Table with the given header cells (if any).
+func NewTable(header ...string) *Table {
+	return &Table{Header: header, Rows: [][]string{}}
+}
+
+// Add appends a row of cells to the table.
+func (t *Table) Add(cells ...string) { t.Rows = append(t.Rows, cells) }
+
+// cols returns the number of columns of the widest row (or header).
+func (t *Table) cols() int {
+	n := len(t.Header)
+	for _, r := range t.Rows {
+		if len(r) > n {
+			n = len(r)
+		}
+	}
+	return n
+}
+
+func (t *Table) align(col int) Align {
+	if col < len(t.Align) {
+		return t.Align[col]
+	}
+	return Left
+}
+
+func (t *Table) sep() string {
+	if t.Sep == "" {
+		return "  "
+	}
+	return t.Sep
+}
+
+// widths returns the width of each column after narrowing the widest
+// columns to fit within the table Width.
+func (t *Table) widths() []int {
+	n := t.cols()
+	widths := make([]int, n)
+	measure := func(row []string) {
+		for i, c := range row {
+			for _, line := range strings.Split(c, "\n") {
+				if w := DisplayWidth(line); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+	measure(t.Header)
+	for _, r := range t.Rows {
+		measure(r)
+	}
+	max := t.Width
+	if max == 0 {
+		max = term.Width()
+	}
+	if max < 0 || n == 0 {
+		return widths
+	}
+	avail := max - DisplayWidth(t.sep())*(n-1)
+	for {
+		sum, widest := 0, 0
+		for i, w := range widths {
+			sum += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if sum <= avail || widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// pad fills buf (which must already fit) to width columns according to
+// the alignment.
+func pad(buf string, width int, a Align) string {
+	n := width - DisplayWidth(buf)
+	if n <= 0 {
+		return buf
+	}
+	switch a {
+	case Right:
+		return strings.Repeat(" ", n) + buf
+	case Center:
+		return strings.Repeat(" ", n/2) + buf + strings.Repeat(" ", n-n/2)
+	}
+	return buf + strings.Repeat(" ", n)
+}
+
+// lines returns the cell as one or more lines each fitting the width.
+func (t *Table) lines(cell string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(cell, "\n") {
+		if DisplayWidth(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+		if !t.Wrap {
+			lines = append(lines, Truncate(line, width))
+			continue
+		}
+		for _, w := range strings.Split(Wrap(StripEsc(line), width), "\n") {
+			for DisplayWidth(w) > width {
+				f := Fit(w, width)
+				if f == "" {
+					f = string([]rune(w)[:1])
+				}
+				lines = append(lines, f)
+				w = w[len(f):]
+			}
+			lines = append(lines, w)
+		}
+	}
+	return lines
+}
+
+// String renders the table as aligned columns fitting within Width.
+// Trailing spaces are never added to the last column.
+func (t *Table) String() string {
+	var buf strings.Builder
+	widths := t.widths()
+	row := func(cells []string, style string) {
+		cols := make([][]string, len(widths))
+		height := 1
+		for i := range widths {
+			if i < len(cells) {
+				cols[i] = t.lines(cells[i], widths[i])
+			}
+			if len(cols[i]) > height {
+				height = len(cols[i])
+			}
+		}
+		for l := 0; l < height; l++ {
+			var line strings.Builder
+			for i, w := range widths {
+				if i > 0 {
+					line.WriteString(t.sep())
+				}
+				var c string
+				if l < len(cols[i]) {
+					c = cols[i][l]
+				}
+				if style != "" && c != "" {
+					c = style + c + reset
+				}
+				line.WriteString(pad(c, w, t.align(i)))
+			}
+			buf.WriteString(strings.TrimRight(line.String(), " "))
+			buf.WriteString("\n")
+		}
+	}
+	if len(t.Header) > 0 {
+		row(t.Header, bold)
+	}
+	for _, r := range t.Rows {
+		row(r, "")
+	}
+	return buf.String()
+}
+
+// TSV renders the table as tab-separated values with a header line (if
+// any). Tabs and line returns within cells are replaced with spaces and
+// all terminal escapes are removed.
+func (t *Table) TSV() string {
+	var buf strings.Builder
+	line := func(cells []string) {
+		c := make([]string, len(cells))
+		for i, s := range cells {
+			c[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").
+				Replace(StripEsc(s))
+		}
+		buf.WriteString(strings.Join(c, "\t") + "\n")
+	}
+	if len(t.Header) > 0 {
+		line(t.Header)
+	}
+	for _, r := range t.Rows {
+		line(r)
+	}
+	return buf.String()
+}
+
+// CSV renders the table as comma-separated values (RFC 4180) with
+// a header line (if any) and all terminal escapes removed.
+func (t *Table) CSV() string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	line := func(cells []string) {
+		c := make([]string, len(cells))
+		for i, s := range cells {
+			c[i] = StripEsc(s)
+		}
+		w.Write(c)
+	}
+	if len(t.Header) > 0 {
+		line(t.Header)
+	}
+	for _, r := range t.Rows {
+		line(r)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// Data returns the table as a slice of maps keyed by the Header cells
+// with all terminal escapes removed. Without a Header each row is
+// returned as a slice of strings instead. Cells beyond the Header are
+// omitted. Data is suitable for marshaling (see JSON).
+func (t *Table) Data() interface{} {
+	if len(t.Header) == 0 {
+		rows := make([][]string, len(t.Rows))
+		for i, r := range t.Rows {
+			rows[i] = make([]string, len(r))
+			for n, c := range r {
+				rows[i][n] = StripEsc(c)
+			}
+		}
+		return rows
+	}
+	rows := make([]map[string]string, len(t.Rows))
+	for i, r := range t.Rows {
+		rows[i] = map[string]string{}
+		for n, h := range t.Header {
+			var c string
+			if n < len(r) {
+				c = StripEsc(r[n])
+			}
+			rows[i][StripEsc(h)] = c
+		}
+	}
+	return rows
+}
+
+// JSON renders the table Data as a JSON array of objects keyed by the
+// Header cells (or an array of arrays without a Header).
+func (t *Table) JSON() string { return MustJSON(t.Data()) }
+
+// Markdown renders the table as a GitHub Flavored Markdown table with
+// alignment markers and all terminal escapes removed. Pipes within cells
+// are escaped and line returns replaced with <br>. Since Markdown
+// requires a header row an empty one is used if Header is not set.
+func (t *Table) Markdown() string {
+	var buf strings.Builder
+	n := t.cols()
+	line := func(cells []string) {
+		buf.WriteString("|")
+		for i := 0; i < n; i++ {
+			var c string
+			if i < len(cells) {
+				c = strings.NewReplacer("|", "\\|", "\n", "<br>").
+					Replace(StripEsc(cells[i]))
+			}
+			buf.WriteString(" " + c + " |")
+		}
+		buf.WriteString("\n")
+	}
+	line(t.Header)
+	buf.WriteString("|")
+	for i := 0; i < n; i++ {
+		switch t.align(i) {
+		case Right:
+			buf.WriteString(" ---: |")
+		case Center:
+			buf.WriteString(" :---: |")
+		default:
+			buf.WriteString(" --- |")
+		}
+	}
+	buf.WriteString("\n")
+	for _, r := range t.Rows {
+		line(r)
+	}
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleTable() {
+	t := util.NewTable("NAME", "SIZE", "NOTE")
+	t.Align = []util.Align{util.Left, util.Right}
+	t.Add("foo", "12", "the first")
+	t.Add("日本", "3456", "wide")
+	t.Add("bar", "7")
+	fmt.Print(t)
+
+	// Output:
+	// NAME  SIZE  NOTE
+	// foo     12  the first
+	// 日本  3456  wide
+	// bar      7
+}
+
+func ExampleTable_truncate() {
+	t := util.NewTable()
+	t.Width = 24
+	t.Add("foo", "a rather long description of foo")
+	t.Add("bar", "short")
+	fmt.Print(t)
+
+	// Output:
+	// foo  a rather long de...
+	// bar  short
+}
+
+func ExampleTable_wrap() {
+	t := util.NewTable()
+	t.Width = 24
+	t.Wrap = true
+	t.Add("foo", "a rather long description of foo")
+	t.Add("bar", "short")
+	fmt.Print(t)
+
+	// Output:
+	// foo  a rather long
+	//      description of foo
+	// bar  short
+}
+
+func ExampleTable_TSV() {
+	t := util.NewTable("name", "note")
+	t.Add("foo", "with\ttab")
+	t.Add("bar", "\033[1mbold\033[0m")
+	fmt.Print(t.TSV())
+
+	// Output:
+	// name	note
+	// foo	with tab
+	// bar	bold
+}
+
+func ExampleTable_CSV() {
+	t := util.NewTable("name", "note")
+	t.Add("foo", "one, two")
+	t.Add("bar", `say "hi"`)
+	fmt.Print(t.CSV())
+
+	// Output:
+	// name,note
+	// foo,"one, two"
+	// bar,"say ""hi"""
+}
+
+func ExampleTable_JSON() {
+	t := util.NewTable("name", "size")
+	t.Add("foo", "12")
+	fmt.Println(t.JSON())
+
+	// Output:
+	// [
+	//     {
+	//       "name": "foo",
+	//       "size": "12"
+	//     }
+	//   ]
+}
+
+func ExampleTable_Markdown() {
+	t := util.NewTable("name", "size", "note")
+	t.Align = []util.Align{util.Left, util.Right, util.Center}
+	t.Add("foo", "12", "a|b")
+	fmt.Print(t.Markdown())
+
+	// Output:
+	// | name | size | note |
+	// | --- | ---: | :---: |
+	// | foo | 12 | a\|b |
+}
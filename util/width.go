@@ -0,0 +1,104 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import "unicode"
+
+// wide contains the East Asian Wide and Fullwidth ranges (and the
+// common emoji blocks) that take two columns of a terminal
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x18aff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f900, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+		{0x20000, 0x3fffd, 1},
+	},
+}
+
+// RuneWidth returns the number of terminal columns taken by the rune:
+// zero for control and combining runes, two for East Asian wide and
+// fullwidth runes (and most emoji), and one for everything else.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.IsControl(r) ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1100 && unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// DisplayWidth returns the number of terminal columns buf will take
+// when printed ignoring any terminal escape sequences (see StripEsc) and
+// observing the width of each rune (see RuneWidth).
+func DisplayWidth(buf string) int {
+	var n int
+	for _, r := range StripEsc(buf) {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// Truncate returns buf shortened to fit within width terminal columns
+// ending with an ellipsis (...) if it had to be shortened. Any terminal
+// escape sequences are removed from buf if it is shortened.
+func Truncate(buf string, width int) string {
+	if DisplayWidth(buf) <= width {
+		return buf
+	}
+	if width <= 3 {
+		return Fit(StripEsc(buf), width)
+	}
+	return Fit(StripEsc(buf), width-3) + "..."
+}
+
+// Fit returns the longest beginning of buf (which should not contain
+// terminal escapes) that fits within width terminal columns.
+func Fit(buf string, width int) string {
+	var n int
+	for i, r := range buf {
+		n += RuneWidth(r)
+		if n > width {
+			return buf[:i]
+		}
+	}
+	return buf
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox/util"
+)
+
+func ExampleDisplayWidth() {
+	fmt.Println(util.DisplayWidth("hello"))
+	fmt.Println(util.DisplayWidth("\033[1mhello\033[0m"))
+	fmt.Println(util.DisplayWidth("日本語"))
+	fmt.Println(util.DisplayWidth("é")) // combining accent
+
+	// Output:
+	// 5
+	// 5
+	// 6
+	// 1
+}
+
+func ExampleTruncate() {
+	fmt.Println(util.Truncate("hello world", 20))
+	fmt.Println(util.Truncate("hello world", 8))
+	fmt.Println(util.Truncate("日本語のテキスト", 9))
+	fmt.Println(util.Truncate("\033[1mhello world\033[0m", 8))
+
+	// Output:
+	// hello world
+	// hello...
+	// 日本語...
+	// hello...
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmdbox
+
+import "sort"
+
+// Validate checks the register for problems that would otherwise only
+// be found at runtime and returns an error for each (or nil if there
+// are none). Currently, Validate finds every cycle of Defaults (a Command
+// without its own Method, Handler, or Result whose Default eventually
+// leads back to itself) and Deprecated replacements (which are always
+// forwarded to, see Deprecation) returning a Cycle error naming each
+// loop once. Commands added with AddLazy are built first so that they
+// are checked as well. Validate is called at Execute time when DEBUG is
+// set. Call it from tests to catch such mistakes early.
+//
+func Validate() []error { return Default.Validate() }
+
+// Validate is the Box equivalent of the package Validate function.
+func (b *Box) Validate() []error {
+
+	// next returns the Command that x forwards to as deprecated or
+	// defaults to (if any) resolved the same way Resolve would
+	// (replacement by name alone, Default qualified name first)
+	next := func(x *Command) *Command {
+		x.load()
+		if d := x.Deprecated; d != nil && d.Replacement != "" {
+			return b.Get(d.Replacement)
+		}
+		if x.Method != nil || x.Handler != nil || x.Result != nil ||
+			x.Default == "" {
+			return nil
+		}
+		if n := b.Get(x.Name + " " + x.Default); n != nil {
+			return n
+		}
+		return b.Get(x.Default)
+	}
+
+	var errs []error
+	seen := map[*Command]bool{}
+	for _, name := range b.Names() {
+		x := b.Get(name)
+		if seen[x] {
+			continue
+		}
+		var chain []*Command
+		at := map[*Command]int{}
+		for c := x; c != nil && !seen[c]; c = next(c) {
+			if i, in := at[c]; in {
+				loop := chain[i:]
+				errs = append(errs, Cycle(cycleNames(loop)))
+				break
+			}
+			at[c] = len(chain)
+			chain = append(chain, c)
+		}
+		for _, c := range chain {
+			seen[c] = true
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	return errs
+}
+
+// cycleNames returns the names of the loop rotated to start with the
+// lowest (so that each cycle is always reported the same way) and ending
+// with the first again.
+func cycleNames(loop []*Command) []string {
+	start := 0
+	for i, c := range loop {
+		if c.Name < loop[start].Name {
+			start = i
+		}
+	}
+	names := []string{}
+	for i := range loop {
+		names = append(names, loop[(start+i)%len(loop)].Name)
+	}
+	return append(names, names[0])
+}
+
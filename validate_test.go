@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Robert S. Muhlestein.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package cmdbox_test
+
+import (
+	"fmt"
+
+	"github.com/rwxrob/cmdbox"
+)
+
+func ExampleCall_cycle() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	foo := cmdbox.Add("foo", "bar")
+	foo.Default = "bar"
+	bar := cmdbox.Add("foo bar")
+	bar.Default = "foo"
+
+	fmt.Println(cmdbox.Call(nil, "foo"))
+
+	// Output:
+	// command cycle: foo -> foo bar -> foo
+}
+
+func ExampleBox_MaxDepth() {
+	b := cmdbox.NewBox()
+	b.MaxDepth = 2
+
+	b.Add("a", "b")
+	b.Add("a b", "c")
+	c := b.Add("a b c")
+	c.Method = func(args ...string) error {
+		fmt.Println("called with", args)
+		return nil
+	}
+
+	fmt.Println(b.Call(nil, "a", "b", "c"))
+	b.MaxDepth = 3
+	fmt.Println(b.Call(nil, "a", "b", "c", "d"))
+
+	// Output:
+	// command resolution too deep (max 2): a -> a b -> a b c
+	// called with [d]
+	// <nil>
+}
+
+func ExampleValidate() {
+	cmdbox.TestOn()
+	defer cmdbox.TestOff()
+
+	fmt.Println(cmdbox.Validate())
+
+	foo := cmdbox.Add("foo", "bar")
+	foo.Default = "bar"
+	bar := cmdbox.Add("foo bar")
+	bar.Default = "foo"
+
+	self := cmdbox.Add("self")
+	self.Default = "self"
+
+	ok := cmdbox.Add("ok", "run")
+	ok.Default = "run"
+	run := cmdbox.Add("ok run")
+	run.Default = "ok"
+	run.Method = func(args ...string) error { return nil }
+
+	for _, err := range cmdbox.Validate() {
+		fmt.Println(err)
+	}
+
+	old := cmdbox.Add("old")
+	old.Deprecated = &cmdbox.Deprecation{Replacement: "new"}
+	old.Method = func(args ...string) error { return nil }
+	cmdbox.AddLazy("new", "the new one", func(x *cmdbox.Command) {
+		x.Deprecated = &cmdbox.Deprecation{Replacement: "old"}
+	})
+
+	for _, err := range cmdbox.Validate() {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// []
+	// command cycle: foo -> foo bar -> foo
+	// command cycle: self -> self
+	// command cycle: foo -> foo bar -> foo
+	// command cycle: new -> old -> new
+	// command cycle: self -> self
+}